@@ -69,6 +69,17 @@ func BenchmarkSliceGoMapster(b *testing.B) {
 	}
 }
 
+// go-mapster MapSlice 基准测试
+func BenchmarkSliceGoMapsterMapSlice(b *testing.B) {
+	src := getUsers(10)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		dst, _ := mapster.MapSlice[User, UserDTO](src)
+		_ = dst
+	}
+}
+
 // jinzhu/copier 基准测试
 func BenchmarkSliceJinzhuCopier(b *testing.B) {
 	src := getUsers(10)
@@ -130,3 +141,46 @@ func BenchmarkSliceHuanduClone(b *testing.B) {
 		_ = dst
 	}
 }
+
+// 基本类型切片转换基准测试
+func getInt32s(count int) []int32 {
+	values := make([]int32, count)
+	for i := range values {
+		values[i] = int32(i)
+	}
+	return values
+}
+
+func BenchmarkPrimitiveSliceManualMapping(b *testing.B) {
+	src := getInt32s(1000)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		dst := make([]int64, len(src))
+		for j, v := range src {
+			dst[j] = int64(v)
+		}
+		_ = dst
+	}
+}
+
+func BenchmarkPrimitiveSliceGoMapster(b *testing.B) {
+	src := getInt32s(1000)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		dst, _ := mapster.Map[[]int64](src)
+		_ = dst
+	}
+}
+
+// Weakly typed conversions are not done in bulk, so every element is mapped one by one
+func BenchmarkPrimitiveSliceGoMapsterPerElement(b *testing.B) {
+	src := getInt32s(1000)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		dst, _ := mapster.Map[[]int64](src, mapster.WeaklyTyped())
+		_ = dst
+	}
+}
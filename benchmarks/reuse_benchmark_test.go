@@ -0,0 +1,91 @@
+package benchmarks
+
+import (
+	"testing"
+
+	"github.com/deferz/go-mapster"
+)
+
+// 复用目标对象的结构体定义
+type UserList struct {
+	Users []User
+}
+
+type UserListDTO struct {
+	Users []UserDTO
+}
+
+// 每次分配新切片的基准测试
+func BenchmarkReuseMapSlice(b *testing.B) {
+	src := getUsers(10)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		dst, _ := mapster.MapSlice[User, UserDTO](src)
+		_ = dst
+	}
+}
+
+// MapSliceInto 复用切片容量的基准测试
+func BenchmarkReuseMapSliceInto(b *testing.B) {
+	src := getUsers(10)
+	dst := make([]UserDTO, 0, len(src))
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		dst, _ = mapster.MapSliceInto(dst[:0], src)
+	}
+}
+
+// MapTo 每次替换切片的基准测试
+func BenchmarkReuseMapTo(b *testing.B) {
+	src := UserList{Users: getUsers(10)}
+	var dst UserListDTO
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = mapster.MapTo(src, &dst)
+	}
+}
+
+// MapTo 复用切片容量的基准测试
+func BenchmarkReuseMapToReuseCapacity(b *testing.B) {
+	src := UserList{Users: getUsers(10)}
+	var dst UserListDTO
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = mapster.MapTo(src, &dst, mapster.ReuseCapacity())
+	}
+}
+
+// Map 每次分配目标对象的基准测试
+func BenchmarkReuseMap(b *testing.B) {
+	src := UserList{Users: getUsers(10)}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		dst, _ := mapster.Map[*UserListDTO](src)
+		_ = dst
+	}
+}
+
+// Pool 复用目标对象的基准测试
+func BenchmarkReusePool(b *testing.B) {
+	src := UserList{Users: getUsers(10)}
+	pool := mapster.NewPool[UserListDTO](func(dst *UserListDTO) {
+		dst.Users = dst.Users[:0]
+	})
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		dst, _ := pool.Map(src, mapster.ReuseCapacity())
+		pool.Put(dst)
+	}
+}
@@ -0,0 +1,150 @@
+package mapster
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/deferz/go-mapster/internal/cache"
+	"github.com/deferz/go-mapster/internal/mapper"
+)
+
+// Pair is implemented by mapping configurations and identifies a source/target type pair.
+// It is used to pass derived configurations to Include.
+type Pair interface {
+	pairConfig() *mapper.PairConfig
+}
+
+// MapperConfig configures the mapping from source type S to target type D.
+// Configurations take effect once Register is called.
+type MapperConfig[S any, D any] struct {
+	pair *mapper.PairConfig
+}
+
+// NewMapperConfig creates a new configuration for mapping S to D
+func NewMapperConfig[S any, D any]() *MapperConfig[S, D] {
+	return &MapperConfig[S, D]{
+		pair: &mapper.PairConfig{
+			Src: typeOf[S](),
+			Dst: typeOf[D](),
+		},
+	}
+}
+
+// Include adds derived pairs used for polymorphic mapping.
+// When a value of this pair's source type is mapped to its target type, the derived
+// pair whose source type matches the value's dynamic type is used instead.
+// Mapping fails if no derived pair matches.
+func (c *MapperConfig[S, D]) Include(derived ...Pair) *MapperConfig[S, D] {
+	for _, pair := range derived {
+		c.pair.Derived = append(c.pair.Derived, pair.pairConfig())
+	}
+	return c
+}
+
+// With adds options applied whenever this pair is mapped, including when it is
+// nested inside another mapping. Pair options take precedence over call options.
+func (c *MapperConfig[S, D]) With(opts ...Option) *MapperConfig[S, D] {
+	for _, opt := range opts {
+		c.pair.Options = append(c.pair.Options, opt)
+	}
+	return c
+}
+
+// ConvertUsing maps values of this pair with fn instead of matching their members.
+// The context is the one passed to MapCtx or MapToCtx, or the background context.
+func (c *MapperConfig[S, D]) ConvertUsing(fn func(ctx context.Context, src S) (D, error)) *MapperConfig[S, D] {
+	c.pair.Convert = func(ctx context.Context, src, dst reflect.Value) error {
+		result, err := fn(ctx, src.Interface().(S))
+		if err != nil {
+			return err
+		}
+		dst.Set(reflect.ValueOf(&result).Elem())
+		return nil
+	}
+	return c
+}
+
+// AfterMap adds a hook called with the source and the mapped target once a value
+// of this pair has been mapped. Hooks run in the order they were added.
+func (c *MapperConfig[S, D]) AfterMap(fn func(ctx context.Context, src S, dst *D) error) *MapperConfig[S, D] {
+	c.pair.AfterMap = append(c.pair.AfterMap, func(ctx context.Context, src, dst reflect.Value) error {
+		return fn(ctx, src.Interface().(S), dst.Addr().Interface().(*D))
+	})
+	return c
+}
+
+// ForMember resolves the target struct member with the given name from the value
+// returned by fn, which is then mapped to the member's type. A nil value leaves
+// the member untouched. S and D must be struct types.
+func (c *MapperConfig[S, D]) ForMember(name string, fn func(ctx context.Context, src S) (any, error)) *MapperConfig[S, D] {
+	if c.pair.Members == nil {
+		c.pair.Members = make(map[string]mapper.MemberFunc)
+	}
+	c.pair.Members[name] = func(ctx context.Context, src reflect.Value) (any, error) {
+		return fn(ctx, src.Interface().(S))
+	}
+	return c
+}
+
+// Register registers the configuration and its derived pairs.
+// It panics if a derived pair is not compatible with this pair,
+// or if a member resolver names a member the target type does not have.
+func (c *MapperConfig[S, D]) Register() {
+	if err := validateMembers(c.pair); err != nil {
+		panic(err)
+	}
+	for _, derived := range c.pair.Derived {
+		if err := validateDerived(c.pair, derived); err != nil {
+			panic(err)
+		}
+		registerPair(derived)
+	}
+	registerPair(c.pair)
+}
+
+// pairConfig implements Pair
+func (c *MapperConfig[S, D]) pairConfig() *mapper.PairConfig {
+	return c.pair
+}
+
+// registerPair stores the pair in the registry and the type cache
+func registerPair(pair *mapper.PairConfig) {
+	mapper.GetGlobalRegistry().Register(pair)
+	cache.GetGlobalCache().RegisterMapping(pair.Src, pair.Dst)
+}
+
+// validateDerived checks that a derived pair can stand in for its base pair
+func validateDerived(base, derived *mapper.PairConfig) error {
+	if base.Src.Kind() == reflect.Interface && !derived.Src.Implements(base.Src) &&
+		!reflect.PtrTo(derived.Src).Implements(base.Src) {
+		return fmt.Errorf("mapster: derived source %s does not implement %s", derived.Src, base.Src)
+	}
+	if !derived.Dst.AssignableTo(base.Dst) {
+		return fmt.Errorf("mapster: derived target %s is not assignable to %s", derived.Dst, base.Dst)
+	}
+	return nil
+}
+
+// validateMembers checks that the pair's member resolvers name exported fields of a struct target
+func validateMembers(pair *mapper.PairConfig) error {
+	if len(pair.Members) == 0 {
+		return nil
+	}
+	if pair.Src.Kind() != reflect.Struct || pair.Dst.Kind() != reflect.Struct {
+		return fmt.Errorf("mapster: member resolvers require struct types, got %s -> %s", pair.Src, pair.Dst)
+	}
+
+	typeInfo := cache.GetGlobalCache().GetOrCreate(pair.Dst)
+	for name := range pair.Members {
+		if _, exists := typeInfo.FieldsMap[name]; !exists {
+			return fmt.Errorf("mapster: %w %q in %s", ErrUnmappedMember, name, pair.Dst)
+		}
+	}
+	return nil
+}
+
+// typeOf returns the reflect type of T, including interface types
+func typeOf[T any]() reflect.Type {
+	return reflect.TypeOf((*T)(nil)).Elem()
+}
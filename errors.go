@@ -0,0 +1,28 @@
+package mapster
+
+import "github.com/deferz/go-mapster/internal/mapper"
+
+// Sentinel errors wrapped by mapping failures, to be checked with errors.Is
+var (
+	// ErrNilSource reports a nil source, or a nil value on a key selector path
+	ErrNilSource = mapper.ErrNilSource
+	// ErrNilDestination reports a nil destination pointer passed to MapTo
+	ErrNilDestination = mapper.ErrNilDestination
+	// ErrNotSettable reports a target value that cannot be assigned
+	ErrNotSettable = mapper.ErrNotSettable
+	// ErrUnconvertible reports a source value that cannot be converted to the target type
+	ErrUnconvertible = mapper.ErrUnconvertible
+	// ErrCycle reports a circular reference under the default CycleError policy
+	ErrCycle = mapper.ErrCycle
+	// ErrLimitExceeded reports a mapping that exceeded MaxDepth, MaxCollectionLen or MaxElements
+	ErrLimitExceeded = mapper.ErrLimitExceeded
+	// ErrUnmappedMember reports a field mask, key field or sync key naming a member that does not exist
+	ErrUnmappedMember = mapper.ErrUnmappedMember
+	// ErrInvalidTag reports a `mapster` tag that cannot apply to its field, such as a remain field that is not a map with string keys
+	ErrInvalidTag = mapper.ErrInvalidTag
+	// ErrMissingKey reports a slice mapped to a map without KeyBy or KeyFunc, a sync strategy
+	// without a key, or a key function returning nil
+	ErrMissingKey = mapper.ErrMissingKey
+	// ErrDuplicateKey reports two slice elements selecting the same map key under DuplicateKeyError
+	ErrDuplicateKey = mapper.ErrDuplicateKey
+)
@@ -1,10 +1,19 @@
 package cache
 
 import (
+	"errors"
+	"fmt"
 	"reflect"
+	"strings"
 	"sync"
 )
 
+// TagName is the struct tag key used to configure field mapping
+const TagName = "mapster"
+
+// ErrInvalidTag reports a `mapster` tag that cannot apply to the field it is set on
+var ErrInvalidTag = errors.New("invalid mapster tag")
+
 // AnonymousFieldInfo stores information about an anonymous (embedded) field
 type AnonymousFieldInfo struct {
 	Type      reflect.Type // 匹名字段的类型
@@ -31,6 +40,14 @@ type TypeInfo struct {
 	EmbeddedFieldsMap map[string]EmbeddedFieldInfo
 	// 嵌套字段映射（用于扁平化）
 	NestedFieldsMap map[string]NestedFieldInfo
+	// Field tagged `mapster:",remain"` collecting unmatched members, nil if none.
+	// It is not part of Fields and is never matched by name.
+	RemainField *FieldInfo
+	// Whether any field has a tag overriding mapping options
+	HasTagOptions bool
+	// Error of a field tag that cannot apply to its field, nil if all tags are valid.
+	// Mapping values of the type fails with this error.
+	TagErr error
 }
 
 // FieldInfo stores cached reflection information about a struct field
@@ -42,7 +59,59 @@ type FieldInfo struct {
 	IsPointer   bool
 	IsSlice     bool
 	IsMap       bool
-	IsAnonymous bool // 是否是匹名字段
+	IsAnonymous bool     // 是否是匹名字段
+	Tag         FieldTag // mapster 标签选项
+}
+
+// FieldTag stores the options parsed from a field's `mapster` tag.
+// Tags have the form `mapster:",option1,option2"`; the part before the first comma is reserved and ignored.
+type FieldTag struct {
+	Remain     bool   // Collects source members not consumed by other fields
+	Collection string // Collection strategy: "replace", "append", "mergeindex" or "sync"
+	SyncKey    string // Identity member used by the "sync" strategy, set with "sync=Key"
+	MapMerge   string // Map strategy: "merge" or "deepmerge"
+	Prune      bool   // Removes target elements or keys missing from the source when syncing or merging
+	IgnoreNil  bool   // Keeps the target value when the source is nil, set with "omitnil"
+	IgnoreZero bool   // Keeps the target value when the source is zero, set with "omitzero"
+}
+
+// OverridesOptions reports whether the tag overrides mapping options for the field
+func (t FieldTag) OverridesOptions() bool {
+	return t.Collection != "" || t.MapMerge != "" || t.IgnoreNil || t.IgnoreZero
+}
+
+// parseFieldTag parses the `mapster` tag of a struct field
+func parseFieldTag(field reflect.StructField) FieldTag {
+	var tag FieldTag
+
+	value, ok := field.Tag.Lookup(TagName)
+	if !ok {
+		return tag
+	}
+
+	options := strings.Split(value, ",")
+	for _, option := range options[1:] {
+		option = strings.TrimSpace(option)
+		switch {
+		case option == "remain":
+			tag.Remain = true
+		case option == "replace" || option == "append" || option == "mergeindex":
+			tag.Collection = option
+		case option == "omitnil":
+			tag.IgnoreNil = true
+		case option == "omitzero":
+			tag.IgnoreZero = true
+		case option == "merge" || option == "deepmerge":
+			tag.MapMerge = option
+		case strings.HasPrefix(option, "sync="):
+			tag.Collection = "sync"
+			tag.SyncKey = strings.TrimPrefix(option, "sync=")
+		case option == "prune":
+			tag.Prune = true
+		}
+	}
+
+	return tag
 }
 
 // EmbeddedFieldInfo stores information about a field in an embedded struct
@@ -231,6 +300,23 @@ func BuildTypeInfo(t reflect.Type) *TypeInfo {
 				IsSlice:     fieldType.Kind() == reflect.Slice,
 				IsMap:       fieldType.Kind() == reflect.Map,
 				IsAnonymous: field.Anonymous,
+				Tag:         parseFieldTag(field),
+			}
+
+			// Remain fields must be maps with string keys and are kept apart from regular fields
+			if fieldInfo.Tag.Remain {
+				if fieldType.Kind() != reflect.Map || fieldType.Key().Kind() != reflect.String {
+					info.TagErr = fmt.Errorf("%w: remain field %s.%s must be a map with string keys, not %s",
+						ErrInvalidTag, t, field.Name, fieldType)
+					continue
+				}
+				remain := fieldInfo
+				info.RemainField = &remain
+				continue
+			}
+
+			if fieldInfo.Tag.OverridesOptions() {
+				info.HasTagOptions = true
 			}
 
 			info.Fields = append(info.Fields, fieldInfo)
@@ -271,7 +357,7 @@ func BuildTypeInfo(t reflect.Type) *TypeInfo {
 				}
 
 				// 收集嵌套字段（用于扁平化映射）
-				collectNestedFields(info, actualType, []string{field.Name}, []int{i}, isPointer)
+				collectNestedFields(info, actualType, []string{field.Name}, []int{i}, isPointer, []reflect.Type{t})
 			}
 		}
 	}
@@ -359,12 +445,21 @@ func GetGlobalCache() *TypeCache {
 }
 
 // collectNestedFields 收集嵌套结构体中的字段（用于扁平化映射）
-func collectNestedFields(info *TypeInfo, nestedType reflect.Type, path []string, indexPath []int, parentIsPointer bool) {
+// ancestors 是路径上已经展开的结构体类型，用于跳过递归类型
+func collectNestedFields(info *TypeInfo, nestedType reflect.Type, path []string, indexPath []int, parentIsPointer bool, ancestors []reflect.Type) {
 	// 确保是结构体类型
 	if nestedType.Kind() != reflect.Struct {
 		return
 	}
 
+	// 递归类型（如 Parent *Node）不再展开，避免无限递归
+	for _, ancestor := range ancestors {
+		if ancestor == nestedType {
+			return
+		}
+	}
+	ancestors = append(ancestors[:len(ancestors):len(ancestors)], nestedType)
+
 	// 遍历嵌套结构体的所有字段
 	for i := 0; i < nestedType.NumField(); i++ {
 		field := nestedType.Field(i)
@@ -425,7 +520,7 @@ func collectNestedFields(info *TypeInfo, nestedType reflect.Type, path []string,
 			}
 
 			// 递归收集嵌套字段
-			collectNestedFields(info, actualType, fieldPath, fieldIndexPath, isPointer || parentIsPointer)
+			collectNestedFields(info, actualType, fieldPath, fieldIndexPath, isPointer || parentIsPointer, ancestors)
 		}
 	}
 }
@@ -3,98 +3,154 @@ package mapper
 import (
 	"fmt"
 	"reflect"
+	"sync"
 )
 
-// circularRefTracker 用于跟踪循环引用
-type circularRefTracker struct {
-	visited map[uintptr]bool
+// CyclePolicy decides what happens when a source value refers back to a value
+// that is still being mapped
+type CyclePolicy int
+
+const (
+	// CycleError returns an error with the path of the cycle (default)
+	CycleError CyclePolicy = iota
+	// CycleBreak leaves the target member that closes the cycle nil
+	CycleBreak
+	// CyclePreserve points the target member at the target of the referenced value,
+	// reproducing the cycle. A source pointer mapped into an addressable value target,
+	// such as the root of MapTo, is referenced through the target's address.
+	// Cycles through slices and maps are broken instead.
+	CyclePreserve
+)
+
+// refKey identifies a reference type source value, and the target type it is mapped to
+// when looking up the target built for it
+type refKey struct {
+	ptr uintptr
+	src reflect.Type
+	dst reflect.Type
 }
 
-// newCircularRefTracker 创建新的循环引用跟踪器
-func newCircularRefTracker() *circularRefTracker {
-	return &circularRefTracker{
-		visited: make(map[uintptr]bool),
+// cycleCache caches whether values of a type can take part in a cycle
+var cycleCache sync.Map
+
+// trackRef returns the key tracking a pointer, slice or map source whose type can take part in a cycle
+func trackRef(src reflect.Value) (refKey, bool) {
+	switch src.Kind() {
+	case reflect.Ptr, reflect.Map, reflect.Slice:
+		if src.IsNil() || !mayCycle(src.Type()) {
+			return refKey{}, false
+		}
+		return refKey{ptr: src.Pointer(), src: src.Type()}, true
+	default:
+		return refKey{}, false
 	}
 }
 
-// checkAndMark 检查并标记访问过的对象
-// 如果对象已经被访问过，返回 true（表示存在循环引用）
-func (c *circularRefTracker) checkAndMark(ptr uintptr) bool {
-	if c.visited[ptr] {
+// tracksCycles reports whether mapping src to dst recurses into the values src refers to.
+// Interface targets, pointer targets of non-pointer sources and weakly typed single
+// element slices map the same source again once resolved, so that call tracks it.
+func (s *state) tracksCycles(src, dst reflect.Value) bool {
+	switch dst.Kind() {
+	case reflect.Interface:
+		return false
+	case reflect.Ptr:
+		return src.Kind() == reflect.Ptr
+	case reflect.Slice, reflect.Array:
+		return src.Kind() != reflect.Ptr
+	default:
 		return true
 	}
-	c.visited[ptr] = true
-	return false
 }
 
-// unmark 取消标记（用于退出递归时）
-func (c *circularRefTracker) unmark(ptr uintptr) {
-	delete(c.visited, ptr)
-}
-
-// MapValueWithCircularCheck 带循环引用检测的映射函数
-func MapValueWithCircularCheck(src, dst reflect.Value, tracker *circularRefTracker) error {
-	// 对于可能包含循环引用的类型（指针、切片、Map、接口）
-	if src.Kind() == reflect.Ptr || src.Kind() == reflect.Slice ||
-		src.Kind() == reflect.Map || src.Kind() == reflect.Interface {
-		if src.CanAddr() {
-			ptr := src.UnsafeAddr()
-			if tracker.checkAndMark(ptr) {
-				// 检测到循环引用，返回 nil（不继续映射）
-				return nil
-			}
-			defer tracker.unmark(ptr)
-		}
+// enterRef marks a reference as being mapped at the current path
+func (s *state) enterRef(key refKey) {
+	if s.visiting == nil {
+		s.visiting = make(map[refKey]int)
 	}
+	s.visiting[key] = len(s.path)
+}
 
-	// 调用原始的 MapValue 函数
-	return MapValue(src, dst)
+// leaveRef marks a reference as mapped
+func (s *state) leaveRef(key refKey) {
+	delete(s.visiting, key)
 }
 
-// detectCircularReference 检测结构体字段中的循环引用
-func detectCircularReference(v reflect.Value, path []reflect.Type) error {
-	if !v.IsValid() || v.IsZero() {
-		return nil
+// recordRef remembers the target pointer built for a non-nil source pointer,
+// so that CyclePreserve and PreserveReferences can point back at it
+func (s *state) recordRef(src, dst reflect.Value) {
+	if !s.opts.PreserveReferences && s.opts.Cycles != CyclePreserve {
+		return
+	}
+	if s.refs == nil {
+		s.refs = make(map[refKey]reflect.Value)
 	}
+	s.refs[refKey{ptr: src.Pointer(), src: src.Type(), dst: dst.Type()}] = dst
+}
 
-	vType := v.Type()
+// preservedRef returns the target pointer already built for a source pointer under PreserveReferences
+func (s *state) preservedRef(src reflect.Value, dstType reflect.Type) (reflect.Value, bool) {
+	if !s.opts.PreserveReferences || src.Kind() != reflect.Ptr || src.IsNil() {
+		return reflect.Value{}, false
+	}
+	ref, ok := s.refs[refKey{ptr: src.Pointer(), src: src.Type(), dst: dstType}]
+	return ref, ok
+}
 
-	// 检查是否在路径中已经存在
-	for _, t := range path {
-		if t == vType {
-			return fmt.Errorf("检测到循环引用: %v", vType)
+// handleCycle applies the cycle policy to a target whose source refers back to
+// a value entered at depth of the current path
+func (s *state) handleCycle(dst reflect.Value, key refKey, depth int) error {
+	switch s.opts.Cycles {
+	case CycleBreak:
+	case CyclePreserve:
+		key.dst = dst.Type()
+		if ref, ok := s.refs[key]; ok {
+			dst.Set(ref)
+			return nil
 		}
+	default:
+		err := fmt.Errorf("%w to %s, first mapped at %s", ErrCycle, key.src, describePath(formatPath(s.path[:depth])))
+		return s.mappingError(err, key.src, dst.Type())
 	}
 
-	// 将当前类型添加到路径
-	newPath := append(path, vType)
+	dst.Set(reflect.Zero(dst.Type()))
+	return nil
+}
 
-	// 根据类型递归检查
-	switch v.Kind() {
-	case reflect.Ptr:
-		if !v.IsNil() {
-			return detectCircularReference(v.Elem(), newPath)
-		}
+// mayCycle reports whether values of the type can reach a value of the same
+// type, or an interface that may hold one
+func mayCycle(typ reflect.Type) bool {
+	if cached, ok := cycleCache.Load(typ); ok {
+		return cached.(bool)
+	}
+	result := reachesType(typ, typ, make(map[reflect.Type]bool))
+	cycleCache.Store(typ, result)
+	return result
+}
+
+// reachesType walks the member types of from, looking for target or an interface
+func reachesType(from, target reflect.Type, seen map[reflect.Type]bool) bool {
+	var next []reflect.Type
+	switch from.Kind() {
+	case reflect.Ptr, reflect.Slice, reflect.Array, reflect.Map:
+		next = append(next, from.Elem())
 	case reflect.Struct:
-		// 检查所有字段
-		for i := 0; i < v.NumField(); i++ {
-			if err := detectCircularReference(v.Field(i), newPath); err != nil {
-				return err
+		for i := 0; i < from.NumField(); i++ {
+			if field := from.Field(i); field.PkgPath == "" {
+				next = append(next, field.Type)
 			}
 		}
-	case reflect.Slice, reflect.Array:
-		// 检查元素类型
-		if v.Len() > 0 {
-			return detectCircularReference(v.Index(0), newPath)
+	}
+
+	for _, typ := range next {
+		if typ == target || typ.Kind() == reflect.Interface {
+			return true
 		}
-	case reflect.Map:
-		// 检查键和值类型
-		for _, key := range v.MapKeys() {
-			if err := detectCircularReference(v.MapIndex(key), newPath); err != nil {
-				return err
+		if !seen[typ] {
+			seen[typ] = true
+			if reachesType(typ, target, seen) {
+				return true
 			}
 		}
 	}
-
-	return nil
+	return false
 }
@@ -12,15 +12,16 @@ import (
 // 1. For slices: Creates a brand new slice with length equal to source slice
 // 2. For arrays: Creates a brand new array with length equal to target array type
 // 3. Target will be completely replaced, not preserving original data
-func mapCollection(src, dst reflect.Value) error {
+// Other collection strategies combine the source with the existing target (see strategy.go)
+func (s *state) mapCollection(src, dst reflect.Value) error {
 	// Verify source value is slice or array
 	if src.Kind() != reflect.Slice && src.Kind() != reflect.Array {
-		return fmt.Errorf("source value is not a slice or array, but %s", src.Kind())
+		return fmt.Errorf("%w: source value is not a slice or array, but %s", ErrUnconvertible, src.Kind())
 	}
 
 	// Verify target value is slice or array
 	if dst.Kind() != reflect.Slice && dst.Kind() != reflect.Array {
-		return fmt.Errorf("target value is not a slice or array, but %s", dst.Kind())
+		return fmt.Errorf("%w: target value is not a slice or array, but %s", ErrUnconvertible, dst.Kind())
 	}
 
 	// Get type information from cache
@@ -42,6 +43,26 @@ func mapCollection(src, dst reflect.Value) error {
 		typeCache.Store(dstType, dstTypeInfo)
 	}
 
+	// Combine with the existing target according to the collection strategy.
+	// Fixed-size arrays cannot grow or shrink, so appending and syncing replace them.
+	switch s.opts.Collection {
+	case CollectionAppend:
+		if dst.Kind() == reflect.Slice {
+			return s.appendCollection(src, dst)
+		}
+	case CollectionMergeByIndex:
+		return s.mergeCollectionByIndex(src, dst)
+	case CollectionSyncByKey:
+		if dst.Kind() == reflect.Slice {
+			return s.syncCollectionByKey(src, dst)
+		}
+	}
+
+	// A field mask selecting members of the elements maps them onto the existing elements
+	if s.mask != nil {
+		return s.mergeCollectionByIndex(src, dst)
+	}
+
 	// Get source length
 	srcLen := src.Len()
 
@@ -51,7 +72,7 @@ func mapCollection(src, dst reflect.Value) error {
 	// Create new target value based on target type
 	if dstTypeInfo.IsCollection && dst.Kind() == reflect.Slice {
 		// Slice: Create new slice with same length as source
-		dstVal = reflect.MakeSlice(dst.Type(), srcLen, srcLen)
+		dstVal = s.makeSlice(src, dst, srcLen)
 		mapLen = srcLen
 	} else {
 		// Array: Create new array with length of target array type
@@ -66,14 +87,8 @@ func mapCollection(src, dst reflect.Value) error {
 	}
 
 	// Map each element
-	for i := 0; i < mapLen; i++ {
-		srcElem := src.Index(i)
-		dstElem := dstVal.Index(i)
-
-		// Recursively map element
-		if err := MapValue(srcElem, dstElem); err != nil {
-			return fmt.Errorf("failed to map element at index %d: %w", i, err)
-		}
+	if err := s.mapElements(src, dstVal, mapLen); err != nil {
+		return err
 	}
 
 	// Set new value to target
@@ -81,17 +96,82 @@ func mapCollection(src, dst reflect.Value) error {
 	return nil
 }
 
+// makeSlice returns a slice of n zero elements for the target, reusing the target's
+// backing array when ReuseCapacity is set and it has enough capacity
+func (s *state) makeSlice(src, dst reflect.Value, n int) reflect.Value {
+	if !s.opts.ReuseCapacity || dst.IsNil() || dst.Cap() < n || sharesStorage(src, dst) {
+		return reflect.MakeSlice(dst.Type(), n, n)
+	}
+
+	slice := dst.Slice(0, n)
+	zero := reflect.Zero(dst.Type().Elem())
+	for i := 0; i < n; i++ {
+		slice.Index(i).Set(zero)
+	}
+	return slice
+}
+
+// makeMap returns an empty map for the target, clearing and reusing the target map
+// when ReuseCapacity is set
+func (s *state) makeMap(src, dst reflect.Value) reflect.Value {
+	if !s.opts.ReuseCapacity || dst.IsNil() || sharesStorage(src, dst) {
+		return reflect.MakeMap(dst.Type())
+	}
+
+	for iter := dst.MapRange(); iter.Next(); {
+		dst.SetMapIndex(iter.Key(), reflect.Value{})
+	}
+	return dst
+}
+
+// sharesStorage reports whether a source slice or map uses the storage of the target,
+// which must then not be overwritten while the source is read
+func sharesStorage(src, dst reflect.Value) bool {
+	return src.Kind() == dst.Kind() && src.Pointer() == dst.Pointer()
+}
+
+// mapElements maps the first n elements of a source slice or array onto the elements
+// at the same index of the target, in bulk for primitive elements and concurrently
+// for large collections when enabled
+func (s *state) mapElements(src, dst reflect.Value, n int) error {
+	if handled, err := s.copyElements(src, dst, n); handled {
+		return err
+	}
+	return s.mapEachElement(nil, src, dst, n)
+}
+
+// mapEachElement maps the first n elements one by one, with the compiled plan of the
+// element pair when given, and concurrently for large collections when enabled
+func (s *state) mapEachElement(plan *Plan, src, dst reflect.Value, n int) error {
+	if s.parallelizes(n) {
+		return s.mapElementsParallel(plan, src, dst, n)
+	}
+
+	for i := 0; i < n; i++ {
+		if err := s.checkContext(i); err != nil {
+			return err
+		}
+
+		// Recursively map element
+		if err := s.mapAt(plan, indexSegment(i), indexSegment(i), src.Index(i), dst.Index(i)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // mapMap handles mapping from Map to Map
 // Supports conversion of both keys and values
-func mapMap(src, dst reflect.Value) error {
+// Existing target maps are replaced unless a map merge strategy is configured (see strategy.go)
+func (s *state) mapMap(src, dst reflect.Value) error {
 	// Verify source value is a Map
 	if src.Kind() != reflect.Map {
-		return fmt.Errorf("source value is not a Map, but %s", src.Kind())
+		return fmt.Errorf("%w: source value is not a Map, but %s", ErrUnconvertible, src.Kind())
 	}
 
 	// Verify target value is a Map
 	if dst.Kind() != reflect.Map {
-		return fmt.Errorf("target value is not a Map, but %s", dst.Kind())
+		return fmt.Errorf("%w: target value is not a Map, but %s", ErrUnconvertible, dst.Kind())
 	}
 
 	// Get type information from cache
@@ -113,33 +193,37 @@ func mapMap(src, dst reflect.Value) error {
 		typeCache.Store(dstType, dstTypeInfo)
 	}
 
+	// Update existing target maps in place when merging or when a field mask selects members of the values
+	if (s.opts.Maps != MapReplace || s.mask != nil) && !dst.IsNil() {
+		return s.mergeMap(src, dst)
+	}
+
 	// Get target Map type information from cached type info
 	dstKeyType := dstType.Key()
 	dstElemType := dstType.Elem()
 
 	// Create new target Map
-	dstMap := reflect.MakeMap(dstType)
+	dstMap := s.makeMap(src, dst)
 
 	// Iterate through all key-value pairs in source Map
-	for _, key := range src.MapKeys() {
+	for i, key := range src.MapKeys() {
+		if err := s.checkContext(i); err != nil {
+			return err
+		}
+
 		// Get source value
 		srcValue := src.MapIndex(key)
 
 		// Create target key
-		dstKey := reflect.New(dstKeyType).Elem()
-		// Try direct key conversion first
-		if key.Type() == dstKeyType {
-			dstKey.Set(key)
-		} else if key.Type().ConvertibleTo(dstKeyType) {
-			dstKey.Set(key.Convert(dstKeyType))
-		} else if err := MapValue(key, dstKey); err != nil {
-			return fmt.Errorf("failed to map Map key: %w", err)
+		dstKey, err := s.mapKey(key, dstKeyType)
+		if err != nil {
+			return err
 		}
 
 		// Create target value
 		dstValue := reflect.New(dstElemType).Elem()
-		if err := MapValue(srcValue, dstValue); err != nil {
-			return fmt.Errorf("failed to map Map value: %w", err)
+		if err := s.mapAt(nil, keySegment(dstKey), keySegment(key), srcValue, dstValue); err != nil {
+			return err
 		}
 
 		// Set key-value pair
@@ -150,3 +234,17 @@ func mapMap(src, dst reflect.Value) error {
 	dst.Set(dstMap)
 	return nil
 }
+
+// mapKey converts a source Map key to the target key type
+func (s *state) mapKey(key reflect.Value, dstKeyType reflect.Type) (reflect.Value, error) {
+	dstKey := reflect.New(dstKeyType).Elem()
+	// Try direct key conversion first
+	if key.Type() == dstKeyType {
+		dstKey.Set(key)
+	} else if key.Type().ConvertibleTo(dstKeyType) {
+		dstKey.Set(key.Convert(dstKeyType))
+	} else if err := s.mapValue(key, dstKey); err != nil {
+		return reflect.Value{}, err
+	}
+	return dstKey, nil
+}
@@ -1,22 +1,13 @@
 package mapper
 
 import (
+	"context"
 	"fmt"
 	"reflect"
 
 	"github.com/deferz/go-mapster/internal/cache"
 )
 
-// ValueConverter defines an interface for custom value conversion
-type ValueConverter interface {
-	Convert(src any) (any, error)
-}
-
-// FieldResolver defines an interface for custom field name resolution
-type FieldResolver interface {
-	ResolveField(srcType reflect.Type, dstType reflect.Type, fieldName string) (string, error)
-}
-
 // MapValue is the core mapping function responsible for mapping source value to target value
 // Parameters:
 //   - src: Reflection value of the source
@@ -25,40 +16,230 @@ type FieldResolver interface {
 // Returns:
 //   - error: Returns an error if mapping fails
 func MapValue(src, dst reflect.Value) error {
+	return MapValueWithOptions(src, dst, nil)
+}
+
+// MapValueWithOptions maps source value to target value using the given options.
+// A nil opts uses the default options.
+func MapValueWithOptions(src, dst reflect.Value, opts *Options) error {
+	return MapValueContext(context.Background(), src, dst, opts)
+}
+
+// MapValueContext maps source value to target value using the given options.
+// The context is passed to converters, hooks and resolvers, and mapping stops
+// with the context's error once it is done.
+func MapValueContext(ctx context.Context, src, dst reflect.Value, opts *Options) error {
+	s, err := newCallState(ctx, dst.Type(), opts)
+	if err != nil {
+		return err
+	}
+	return s.finish(s.mapValue(src, dst))
+}
+
+// newCallState creates the state of a mapping call to the target type
+func newCallState(ctx context.Context, dstType reflect.Type, opts *Options) (*state, error) {
+	s := newState(opts).withContext(ctx)
+	if err := s.ctx.Err(); err != nil {
+		return nil, err
+	}
+	if len(s.opts.FieldMask) > 0 {
+		mask, err := compileFieldMask(dstType, s.opts.FieldMask)
+		if err != nil {
+			return nil, err
+		}
+		s.mask = mask
+	}
+	return s, nil
+}
+
+// finish returns the result of a mapping call, reporting the errors collected under CollectErrors
+func (s *state) finish(err error) error {
+	if err != nil {
+		return err
+	}
+	if len(s.errs) > 0 {
+		return s.errs
+	}
+	return nil
+}
+
+// mapValue maps a value within a mapping call, reporting failures and panics
+// as a MappingError at the innermost failing value
+func (s *state) mapValue(src, dst reflect.Value) (err error) {
+	if !s.opts.PropagatePanics {
+		defer s.recoverPanic(src, dst, &err)
+	}
+
+	err = s.mapDispatch(src, dst)
+	if err == nil {
+		return nil
+	}
+
+	var srcType, dstType reflect.Type
+	if src.IsValid() {
+		srcType = src.Type()
+	}
+	if dst.IsValid() {
+		dstType = dst.Type()
+	}
+	return s.mappingError(err, srcType, dstType)
+}
+
+// mapDispatch resolves interfaces, pointers, polymorphic targets and pair options,
+// then maps the value
+func (s *state) mapDispatch(src, dst reflect.Value) error {
 	// Check for nil source
 	if !src.IsValid() {
-		return fmt.Errorf("source value is invalid")
+		return ErrNilSource
 	}
 
 	// Check if target is settable
 	if !dst.CanSet() {
-		return fmt.Errorf("target value is not settable")
+		return ErrNotSettable
+	}
+
+	// Keep the target value when the source is absent in a partial update
+	if s.skips(src) {
+		return nil
 	}
 
 	srcType := src.Type()
 	dstType := dst.Type()
 
-	// If types are identical, assign directly
-	if srcType == dstType {
+	// If types are identical, assign directly unless a registered pair customizes them or their members
+	if srcType == dstType && s.assignsDirectly(dst) && !GetGlobalRegistry().holdsCustomized(srcType) {
+		if s.opts.DeepCopy {
+			return s.deepCopy(src, dst)
+		}
+		// Shared values are not traversed, but their own size still counts against the limits
+		if s.limitsNesting(dst) {
+			if err := s.enterLevel(src, dstType); err != nil {
+				return err
+			}
+			s.leaveLevel()
+		}
 		dst.Set(src)
 		return nil
 	}
 
+	// Unwrap interface values so the dynamic value drives the mapping
+	if src.Kind() == reflect.Interface {
+		if src.IsNil() {
+			return nil // Skip mapping for nil interfaces
+		}
+		return s.mapValue(src.Elem(), dst)
+	}
+
+	// Reuse the target already built for a shared source pointer
+	if ref, ok := s.preservedRef(src, dstType); ok {
+		dst.Set(ref)
+		return nil
+	}
+
+	// Detect references back to a value that is still being mapped
+	if key, tracked := trackRef(src); tracked && s.tracksCycles(src, dst) {
+		if depth, cyclic := s.visiting[key]; cyclic {
+			return s.handleCycle(dst, key, depth)
+		}
+		s.enterRef(key)
+		defer s.leaveRef(key)
+	}
+
+	// Dereference source pointers when the target holds a value
+	if src.Kind() == reflect.Ptr && dst.Kind() != reflect.Ptr && dst.Kind() != reflect.Interface {
+		if src.IsNil() {
+			return nil // Skip mapping for nil pointers
+		}
+		// References back to the source pointer point at the target value, such as a root mapped by value
+		if dst.CanAddr() {
+			s.recordRef(src, dst.Addr())
+		}
+		return s.mapValue(src.Elem(), dst)
+	}
+
+	// Dispatch polymorphic targets to the pair registered for the source's dynamic type
+	if dst.Kind() == reflect.Interface {
+		if derived, base := GetGlobalRegistry().FindDerived(srcType, dstType); base != nil {
+			return s.mapDerived(src, dst, derived, base)
+		}
+	}
+
+	// Apply the options, converter and hooks of a registered pair while mapping it
+	if pair := GetGlobalRegistry().Get(srcType, dstType); pair != nil && pair.customizes() {
+		return s.mapPair(pair, src, dst)
+	}
+
+	return s.mapResolved(src, dst)
+}
+
+// assignsDirectly reports whether a source of the same type can simply be assigned to the target,
+// which is not the case when merge strategies or field tags must combine it with the target
+func (s *state) assignsDirectly(dst reflect.Value) bool {
+	// Field masks select members below this value
+	if s.mask != nil {
+		return false
+	}
+
+	switch dst.Kind() {
+	case reflect.Slice, reflect.Array:
+		return s.opts.Collection == CollectionReplace
+	case reflect.Map:
+		return s.opts.Maps == MapReplace
+	case reflect.Struct:
+		// Deep merges and partial updates recurse into struct values instead of replacing them
+		return s.opts.Maps != MapDeepMerge && !s.merging() && !cache.GetGlobalCache().GetOrCreate(dst.Type()).HasTagOptions
+	case reflect.Ptr:
+		// Partial updates merge into the existing pointee
+		return !s.merging() || dst.IsNil()
+	default:
+		return true
+	}
+}
+
+// mapResolved chooses the mapping strategy once interfaces and pointers have been resolved
+func (s *state) mapResolved(src, dst reflect.Value) error {
+	// Enforce the configured limits on nested values
+	if s.limitsNesting(dst) {
+		if err := s.enterLevel(src, dst.Type()); err != nil {
+			return err
+		}
+		defer s.leaveLevel()
+	}
+
+	// Apply lenient conversions first for weakly typed sources
+	if s.opts.WeaklyTyped {
+		if handled, err := s.mapWeak(src, dst); handled {
+			return err
+		}
+	}
+
 	// Get type information from cache for fast type checking
 	typeCache := cache.GetGlobalCache()
 
 	// Get or build type info
-	dstTypeInfo := typeCache.GetOrCreate(dstType)
+	dstTypeInfo := typeCache.GetOrCreate(dst.Type())
 
 	// Choose mapping strategy based on cached type information
 	if dstTypeInfo.IsStruct {
-		return mapStruct(src, dst)
+		if src.Kind() == reflect.Map {
+			return s.mapMapToStruct(src, dst)
+		}
+		return s.mapStruct(src, dst)
 	} else if dstTypeInfo.IsCollection {
-		return mapCollection(src, dst)
+		if src.Kind() == reflect.Map {
+			return s.mapMapToSlice(src, dst)
+		}
+		return s.mapCollection(src, dst)
 	} else if dstTypeInfo.IsMap {
-		return mapMap(src, dst)
+		if src.Kind() == reflect.Struct {
+			return s.mapStructToMap(src, dst)
+		}
+		if src.Kind() == reflect.Slice || src.Kind() == reflect.Array {
+			return s.mapSliceToMap(src, dst)
+		}
+		return s.mapMap(src, dst)
 	} else if dst.Kind() == reflect.Ptr {
-		return mapPointer(src, dst)
+		return s.mapPointer(src, dst)
 	} else {
 		// Try basic type conversion
 		return mapBasicType(src, dst)
@@ -81,26 +262,34 @@ func mapBasicType(src, dst reflect.Value) error {
 		return nil
 	}
 
-	return fmt.Errorf("cannot convert from %s to %s", srcType, dstType)
+	return fmt.Errorf("%w from %s to %s", ErrUnconvertible, srcType, dstType)
 }
 
 // mapPointer handles pointer type mapping
-func mapPointer(src, dst reflect.Value) error {
-	// If destination is nil pointer, create a new instance
+func (s *state) mapPointer(src, dst reflect.Value) error {
+	// If destination is nil pointer, map into a new instance assigned once mapped
+	ptr := dst
 	if dst.IsNil() {
-		dst.Set(reflect.New(dst.Type().Elem()))
+		ptr = reflect.New(dst.Type().Elem())
 	}
 
-	// If source is nil, do nothing
+	// If source is nil, do nothing but allocate the target
 	if src.Kind() == reflect.Ptr && src.IsNil() {
+		dst.Set(ptr)
 		return nil
 	}
 
-	// If source is not a pointer, map to the pointer's element
-	if src.Kind() != reflect.Ptr {
-		return MapValue(src, dst.Elem())
+	// Remember the target of this source pointer for references pointing back at it
+	if src.Kind() == reflect.Ptr {
+		s.recordRef(src, ptr)
+		src = src.Elem()
+	}
+
+	// Map the source, or the source pointer's element, to the pointer's element
+	if err := s.mapValue(src, ptr.Elem()); err != nil {
+		return err
 	}
 
-	// Both are pointers, map their elements
-	return MapValue(src.Elem(), dst.Elem())
+	dst.Set(ptr)
+	return nil
 }
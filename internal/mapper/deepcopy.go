@@ -0,0 +1,191 @@
+package mapper
+
+import (
+	"reflect"
+	"sync"
+)
+
+// referenceCache caches whether a type holds references that DeepCopy must copy
+var referenceCache sync.Map
+
+// deepCopy copies src into dst of the same type, recursively copying pointers,
+// slices, maps, arrays and interface values instead of sharing them.
+// Shared types and unexported struct fields are copied by assignment.
+func (s *state) deepCopy(src, dst reflect.Value) error {
+	typ := src.Type()
+
+	// Values customized by a registered pair, or holding such values, are mapped instead
+	if GetGlobalRegistry().holdsCustomized(typ) {
+		return s.mapValue(src, dst)
+	}
+
+	if s.sharesType(typ) || !hasReferences(typ) {
+		dst.Set(src)
+		return nil
+	}
+
+	// Enforce the configured limits on nested values
+	if s.limitsNesting(src) {
+		if err := s.enterLevel(src, typ); err != nil {
+			return err
+		}
+		defer s.leaveLevel()
+	}
+
+	// Reuse the copy already made for a shared source pointer
+	if ref, ok := s.preservedRef(src, typ); ok {
+		dst.Set(ref)
+		return nil
+	}
+
+	// Detect references back to a value that is still being copied
+	key, tracked := trackRef(src)
+	if tracked {
+		if depth, cyclic := s.visiting[key]; cyclic {
+			return s.handleCycle(dst, key, depth)
+		}
+		s.enterRef(key)
+		defer s.leaveRef(key)
+	}
+
+	switch src.Kind() {
+	case reflect.Ptr:
+		if src.IsNil() {
+			dst.Set(src)
+			return nil
+		}
+		ptr := reflect.New(typ.Elem())
+		s.recordRef(src, ptr)
+		if err := s.deepCopy(src.Elem(), ptr.Elem()); err != nil {
+			return err
+		}
+		dst.Set(ptr)
+
+	case reflect.Interface:
+		if src.IsNil() || s.sharesType(src.Elem().Type()) {
+			dst.Set(src)
+			return nil
+		}
+		elem := reflect.New(src.Elem().Type()).Elem()
+		if err := s.deepCopy(src.Elem(), elem); err != nil {
+			return err
+		}
+		dst.Set(elem)
+
+	case reflect.Slice:
+		if src.IsNil() {
+			dst.Set(src)
+			return nil
+		}
+		slice := reflect.MakeSlice(typ, src.Len(), src.Len())
+		for i := 0; i < src.Len(); i++ {
+			if err := s.checkContext(i); err != nil {
+				return err
+			}
+			if err := s.deepCopyAt(indexSegment(i), src.Index(i), slice.Index(i)); err != nil {
+				return err
+			}
+		}
+		dst.Set(slice)
+
+	case reflect.Array:
+		for i := 0; i < src.Len(); i++ {
+			if err := s.checkContext(i); err != nil {
+				return err
+			}
+			if err := s.deepCopyAt(indexSegment(i), src.Index(i), dst.Index(i)); err != nil {
+				return err
+			}
+		}
+
+	case reflect.Map:
+		if src.IsNil() {
+			dst.Set(src)
+			return nil
+		}
+		m := reflect.MakeMapWithSize(typ, src.Len())
+		iter := src.MapRange()
+		for i := 0; iter.Next(); i++ {
+			if err := s.checkContext(i); err != nil {
+				return err
+			}
+			value := reflect.New(typ.Elem()).Elem()
+			if err := s.deepCopyAt(keySegment(iter.Key()), iter.Value(), value); err != nil {
+				return err
+			}
+			m.SetMapIndex(iter.Key(), value)
+		}
+		dst.Set(m)
+
+	case reflect.Struct:
+		// Assign first so unexported fields are kept, then copy the exported ones
+		dst.Set(src)
+		for i := 0; i < typ.NumField(); i++ {
+			field := typ.Field(i)
+			if field.PkgPath != "" {
+				continue
+			}
+			if err := s.deepCopyAt(fieldSegment(field.Name), src.Field(i), dst.Field(i)); err != nil {
+				return err
+			}
+		}
+
+	default:
+		// Channels, functions and unsafe pointers are shared
+		dst.Set(src)
+	}
+
+	return nil
+}
+
+// deepCopyAt copies a member of src into the same member of dst, leaving the member even when copying panics
+func (s *state) deepCopyAt(segment pathSegment, src, dst reflect.Value) error {
+	s.pushPath(segment, segment)
+	defer s.popPath()
+	return s.deepCopy(src, dst)
+}
+
+// sharesType reports whether values of the type are shared rather than copied
+func (s *state) sharesType(typ reflect.Type) bool {
+	for _, shared := range s.opts.SharedTypes {
+		if shared == typ {
+			return true
+		}
+	}
+	return false
+}
+
+// hasReferences reports whether values of the type hold pointers, slices, maps or
+// interfaces reachable through exported members
+func hasReferences(typ reflect.Type) bool {
+	if cached, ok := referenceCache.Load(typ); ok {
+		return cached.(bool)
+	}
+	result := computeReferences(typ, make(map[reflect.Type]bool))
+	referenceCache.Store(typ, result)
+	return result
+}
+
+// computeReferences walks the type, using visiting to stop at recursive types
+func computeReferences(typ reflect.Type, visiting map[reflect.Type]bool) bool {
+	switch typ.Kind() {
+	case reflect.Ptr, reflect.Interface, reflect.Slice, reflect.Map:
+		return true
+	case reflect.Array:
+		return typ.Len() > 0 && computeReferences(typ.Elem(), visiting)
+	case reflect.Struct:
+		if visiting[typ] {
+			return false
+		}
+		visiting[typ] = true
+		for i := 0; i < typ.NumField(); i++ {
+			field := typ.Field(i)
+			if field.PkgPath == "" && computeReferences(field.Type, visiting) {
+				return true
+			}
+		}
+		return false
+	default:
+		return false
+	}
+}
@@ -8,7 +8,7 @@ import (
 
 // processEmbeddedFields processes embedded fields in structs
 // Embedded fields are anonymous fields in Go and require special handling
-func processEmbeddedFields(src, dst reflect.Value) error {
+func (s *state) processEmbeddedFields(src, dst reflect.Value) error {
 	srcType := src.Type()
 	dstType := dst.Type()
 
@@ -40,7 +40,7 @@ func processEmbeddedFields(src, dst reflect.Value) error {
 					dstFieldValue := dst.Field(j)
 
 					// Recursively map embedded field
-					if err := MapValue(srcFieldValue, dstFieldValue); err != nil {
+					if err := s.mapValue(srcFieldValue, dstFieldValue); err != nil {
 						return err
 					}
 					break
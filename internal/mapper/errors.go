@@ -0,0 +1,153 @@
+package mapper
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"runtime/debug"
+	"strings"
+
+	"github.com/deferz/go-mapster/internal/cache"
+)
+
+// Sentinel errors wrapped by mapping failures, to be checked with errors.Is
+var (
+	// ErrNilSource reports a nil or invalid source value
+	ErrNilSource = errors.New("source cannot be nil")
+	// ErrNilDestination reports a nil destination pointer
+	ErrNilDestination = errors.New("destination cannot be nil pointer")
+	// ErrNotSettable reports a target value that cannot be assigned
+	ErrNotSettable = errors.New("target value is not settable")
+	// ErrUnconvertible reports a source value that cannot be converted to the target type
+	ErrUnconvertible = errors.New("cannot convert")
+	// ErrCycle reports a reference back to a value that is still being mapped
+	ErrCycle = errors.New("circular reference")
+	// ErrLimitExceeded reports a mapping that exceeded a configured limit
+	ErrLimitExceeded = errors.New("limit exceeded")
+	// ErrUnmappedMember reports a member name that does not exist on the type it is looked up in
+	ErrUnmappedMember = errors.New("no such member")
+	// ErrInvalidTag reports a field tag that cannot apply to its field
+	ErrInvalidTag = cache.ErrInvalidTag
+	// ErrMissingKey reports a keyed mapping without a key selector or sync key, or an element without a key
+	ErrMissingKey = errors.New("missing key")
+	// ErrDuplicateKey reports two slice elements selecting the same map key under DuplicateKeyError
+	ErrDuplicateKey = errors.New("duplicate key")
+)
+
+// MappingError reports a failure to map a value, with the paths of the members involved
+type MappingError struct {
+	DstPath string       // target path such as "Orders[3].Items[1].Price", empty for the root value
+	SrcPath string       // path of the source member mapped to DstPath
+	SrcType reflect.Type // type of the source value
+	DstType reflect.Type // type of the target value
+	Cause   error        // underlying error
+}
+
+// Error implements the error interface
+func (e *MappingError) Error() string {
+	if e.SrcPath != e.DstPath {
+		return fmt.Sprintf("failed to map %s from %s (%v to %v): %v",
+			describePath(e.DstPath), describePath(e.SrcPath), e.SrcType, e.DstType, e.Cause)
+	}
+	return fmt.Sprintf("failed to map %s (%v to %v): %v", describePath(e.DstPath), e.SrcType, e.DstType, e.Cause)
+}
+
+// Unwrap returns the underlying error
+func (e *MappingError) Unwrap() error {
+	return e.Cause
+}
+
+// mappingError wraps cause into a MappingError at the current paths,
+// unless it already carries one from a deeper value
+func (s *state) mappingError(cause error, srcType, dstType reflect.Type) error {
+	var mappingErr *MappingError
+	if errors.As(cause, &mappingErr) {
+		return cause
+	}
+	return &MappingError{
+		DstPath: formatPath(s.path),
+		SrcPath: formatPath(s.srcPath),
+		SrcType: srcType,
+		DstType: dstType,
+		Cause:   cause,
+	}
+}
+
+// PanicError reports a panic raised while mapping a value, such as a reflect
+// operation on an unexpected type combination
+type PanicError struct {
+	Value any    // value passed to panic
+	Stack []byte // stack trace of the goroutine at the panic
+}
+
+// Error implements the error interface
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("panic: %v", e.Value)
+}
+
+// Unwrap returns the panic value when it is an error
+func (e *PanicError) Unwrap() error {
+	err, _ := e.Value.(error)
+	return err
+}
+
+// recoverPanic turns a panic raised while mapping a value into a MappingError at the current paths.
+// It must be deferred directly so that recover stops the panic.
+func (s *state) recoverPanic(src, dst reflect.Value, err *error) {
+	r := recover()
+	if r == nil {
+		return
+	}
+
+	var srcType, dstType reflect.Type
+	if src.IsValid() {
+		srcType = src.Type()
+	}
+	if dst.IsValid() {
+		dstType = dst.Type()
+	}
+	*err = s.mappingError(&PanicError{Value: r, Stack: debug.Stack()}, srcType, dstType)
+}
+
+// MappingErrors lists every field-level failure of a mapping made with CollectErrors
+type MappingErrors []*MappingError
+
+// Error implements the error interface
+func (e MappingErrors) Error() string {
+	if len(e) == 1 {
+		return e[0].Error()
+	}
+
+	messages := make([]string, len(e))
+	for i, err := range e {
+		messages[i] = err.Error()
+	}
+	return fmt.Sprintf("%d mapping errors: %s", len(e), strings.Join(messages, "; "))
+}
+
+// Unwrap returns the collected errors, so errors.Is and errors.As inspect each of them
+func (e MappingErrors) Unwrap() []error {
+	errs := make([]error, len(e))
+	for i, err := range e {
+		errs[i] = err
+	}
+	return errs
+}
+
+// collectError records a field-level failure under CollectErrors so that mapping
+// continues with the next field. Other errors, and limit errors, panics and cancellation
+// which must stop the mapping, are returned as they are.
+func (s *state) collectError(err error) error {
+	if !s.opts.CollectErrors || s.cancelled() {
+		return err
+	}
+
+	var mappingErr *MappingError
+	var limitErr *LimitError
+	var panicErr *PanicError
+	if !errors.As(err, &mappingErr) || errors.As(err, &limitErr) || errors.As(err, &panicErr) {
+		return err
+	}
+	s.errs = append(s.errs, mappingErr)
+	return nil
+}
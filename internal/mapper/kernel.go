@@ -0,0 +1,261 @@
+package mapper
+
+import (
+	"reflect"
+	"unsafe"
+)
+
+// elementKernel converts the first n elements of a source array onto a target array,
+// both given by the address of their first element
+type elementKernel func(dst, src unsafe.Pointer, n int)
+
+// Element types converted by the kernels
+type (
+	signed interface {
+		int | int8 | int16 | int32 | int64
+	}
+	unsigned interface {
+		uint | uint8 | uint16 | uint32 | uint64 | uintptr
+	}
+	integer interface{ signed | unsigned }
+	float   interface{ float32 | float64 }
+)
+
+// copyElements maps the first n elements of a source slice or array of primitives in bulk,
+// as mapping them one by one would assign or convert each of them as it is.
+// Returns handled=false when the elements need to be mapped one by one.
+func (s *state) copyElements(src, dst reflect.Value, n int) (bool, error) {
+	srcElem, dstElem := src.Type().Elem(), dst.Type().Elem()
+	if srcElem == dstElem {
+		if !s.copiesElements(dstElem) {
+			return false, nil
+		}
+		return s.copyBulk(nil, src, dst, n)
+	}
+	return s.convertElements(compileKernel(srcElem, dstElem), src, dst, n)
+}
+
+// convertElements converts the first n elements of a source slice or array with the kernel
+// compiled for their types. Returns handled=false when there is no kernel or the options
+// convert elements otherwise.
+func (s *state) convertElements(kernel elementKernel, src, dst reflect.Value, n int) (bool, error) {
+	// Weakly typed conversions parse and format values instead
+	if kernel == nil || s.opts.WeaklyTyped {
+		return false, nil
+	}
+	return s.copyBulk(kernel, src, dst, n)
+}
+
+// copyBulk copies the first n elements of src to dst as memory, converting them
+// with the kernel when given
+func (s *state) copyBulk(kernel elementKernel, src, dst reflect.Value, n int) (bool, error) {
+	if n == 0 {
+		return false, nil
+	}
+
+	// Bulk copies need the memory of both arrays
+	if dst.Kind() == reflect.Array && !dst.CanAddr() {
+		return false, nil
+	}
+	if src.Kind() == reflect.Array && !src.CanAddr() {
+		addressable := reflect.New(src.Type()).Elem()
+		addressable.Set(src)
+		src = addressable
+	}
+
+	// The whole copy runs in a single step, so the context is checked once
+	if err := s.checkContext(0); err != nil {
+		return true, err
+	}
+
+	if kernel == nil {
+		reflect.Copy(dst.Slice(0, n), src.Slice(0, n))
+	} else {
+		kernel(elementsPointer(dst), elementsPointer(src), n)
+	}
+	return true, nil
+}
+
+// copiesElements reports whether elements of the same type are assigned as they are
+// and hold no pointers other than strings, so that they can be copied in bulk
+func (s *state) copiesElements(elem reflect.Type) bool {
+	if !plainType(elem) {
+		return false
+	}
+
+	switch elem.Kind() {
+	case reflect.Array, reflect.Struct:
+		// Options that recurse into composite elements or count them map them one by one
+		return !s.opts.DeepCopy && s.copiesLayout(nil)
+	default:
+		return true
+	}
+}
+
+// compileKernel returns the kernel converting elements between two primitive types,
+// or nil when mapping them one by one may do more than a Go conversion
+func compileKernel(srcElem, dstElem reflect.Type) elementKernel {
+	if pair := GetGlobalRegistry().Get(srcElem, dstElem); pair != nil && pair.customizes() {
+		return nil
+	}
+
+	switch srcElem.Kind() {
+	case reflect.Bool:
+		if dstElem.Kind() == reflect.Bool {
+			return copyKernel[bool]
+		}
+	case reflect.String:
+		if dstElem.Kind() == reflect.String {
+			return copyKernel[string]
+		}
+	case reflect.Int:
+		return integerKernel[int](dstElem.Kind())
+	case reflect.Int8:
+		return integerKernel[int8](dstElem.Kind())
+	case reflect.Int16:
+		return integerKernel[int16](dstElem.Kind())
+	case reflect.Int32:
+		return integerKernel[int32](dstElem.Kind())
+	case reflect.Int64:
+		return integerKernel[int64](dstElem.Kind())
+	case reflect.Uint:
+		return integerKernel[uint](dstElem.Kind())
+	case reflect.Uint8:
+		return integerKernel[uint8](dstElem.Kind())
+	case reflect.Uint16:
+		return integerKernel[uint16](dstElem.Kind())
+	case reflect.Uint32:
+		return integerKernel[uint32](dstElem.Kind())
+	case reflect.Uint64:
+		return integerKernel[uint64](dstElem.Kind())
+	case reflect.Uintptr:
+		return integerKernel[uintptr](dstElem.Kind())
+	case reflect.Float32:
+		return floatKernel[float32](dstElem.Kind())
+	case reflect.Float64:
+		return floatKernel[float64](dstElem.Kind())
+	}
+	return nil
+}
+
+// integerKernel returns the kernel converting integers of type S to numbers of the target kind
+func integerKernel[S integer](dstKind reflect.Kind) elementKernel {
+	switch dstKind {
+	case reflect.Int:
+		return convertIntegers[S, int]
+	case reflect.Int8:
+		return convertIntegers[S, int8]
+	case reflect.Int16:
+		return convertIntegers[S, int16]
+	case reflect.Int32:
+		return convertIntegers[S, int32]
+	case reflect.Int64:
+		return convertIntegers[S, int64]
+	case reflect.Uint:
+		return convertIntegers[S, uint]
+	case reflect.Uint8:
+		return convertIntegers[S, uint8]
+	case reflect.Uint16:
+		return convertIntegers[S, uint16]
+	case reflect.Uint32:
+		return convertIntegers[S, uint32]
+	case reflect.Uint64:
+		return convertIntegers[S, uint64]
+	case reflect.Uintptr:
+		return convertIntegers[S, uintptr]
+	case reflect.Float32:
+		return convertIntegersToFloats[S, float32]
+	case reflect.Float64:
+		return convertIntegersToFloats[S, float64]
+	}
+	return nil
+}
+
+// floatKernel returns the kernel converting floats of type S to numbers of the target kind
+func floatKernel[S float](dstKind reflect.Kind) elementKernel {
+	switch dstKind {
+	case reflect.Int:
+		return convertFloatsToSigned[S, int]
+	case reflect.Int8:
+		return convertFloatsToSigned[S, int8]
+	case reflect.Int16:
+		return convertFloatsToSigned[S, int16]
+	case reflect.Int32:
+		return convertFloatsToSigned[S, int32]
+	case reflect.Int64:
+		return convertFloatsToSigned[S, int64]
+	case reflect.Uint:
+		return convertFloatsToUnsigned[S, uint]
+	case reflect.Uint8:
+		return convertFloatsToUnsigned[S, uint8]
+	case reflect.Uint16:
+		return convertFloatsToUnsigned[S, uint16]
+	case reflect.Uint32:
+		return convertFloatsToUnsigned[S, uint32]
+	case reflect.Uint64:
+		return convertFloatsToUnsigned[S, uint64]
+	case reflect.Uintptr:
+		return convertFloatsToUnsigned[S, uintptr]
+	case reflect.Float32:
+		return convertFloats[S, float32]
+	case reflect.Float64:
+		return convertFloats[S, float64]
+	}
+	return nil
+}
+
+// The conversions below go through the same intermediate types as reflect.Value.Convert,
+// so that rounding and out-of-range results match mapping the elements one by one
+
+// copyKernel copies booleans or strings between named types
+func copyKernel[T bool | string](dst, src unsafe.Pointer, n int) {
+	copy(unsafe.Slice((*T)(dst), n), unsafe.Slice((*T)(src), n))
+}
+
+// convertIntegers converts integers, truncating or extending them to the target size
+func convertIntegers[S, D integer](dst, src unsafe.Pointer, n int) {
+	srcElems, dstElems := unsafe.Slice((*S)(src), n), unsafe.Slice((*D)(dst), n)
+	for i, v := range srcElems {
+		dstElems[i] = D(v)
+	}
+}
+
+// convertIntegersToFloats converts integers to floats, rounding them to float64 first
+func convertIntegersToFloats[S integer, D float](dst, src unsafe.Pointer, n int) {
+	srcElems, dstElems := unsafe.Slice((*S)(src), n), unsafe.Slice((*D)(dst), n)
+	for i, v := range srcElems {
+		dstElems[i] = D(float64(v))
+	}
+}
+
+// convertFloatsToSigned converts floats to signed integers through int64
+func convertFloatsToSigned[S float, D signed](dst, src unsafe.Pointer, n int) {
+	srcElems, dstElems := unsafe.Slice((*S)(src), n), unsafe.Slice((*D)(dst), n)
+	for i, v := range srcElems {
+		dstElems[i] = D(int64(float64(v)))
+	}
+}
+
+// convertFloatsToUnsigned converts floats to unsigned integers through uint64
+func convertFloatsToUnsigned[S float, D unsigned](dst, src unsafe.Pointer, n int) {
+	srcElems, dstElems := unsafe.Slice((*S)(src), n), unsafe.Slice((*D)(dst), n)
+	for i, v := range srcElems {
+		dstElems[i] = D(uint64(float64(v)))
+	}
+}
+
+// convertFloats converts floats, rounding them to the target precision
+func convertFloats[S, D float](dst, src unsafe.Pointer, n int) {
+	srcElems, dstElems := unsafe.Slice((*S)(src), n), unsafe.Slice((*D)(dst), n)
+	for i, v := range srcElems {
+		dstElems[i] = D(float64(v))
+	}
+}
+
+// elementsPointer returns the address of the first element of a slice or addressable array
+func elementsPointer(v reflect.Value) unsafe.Pointer {
+	if v.Kind() == reflect.Slice {
+		return unsafe.Pointer(v.Pointer())
+	}
+	return unsafe.Pointer(v.UnsafeAddr())
+}
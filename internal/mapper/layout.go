@@ -0,0 +1,208 @@
+package mapper
+
+import (
+	"reflect"
+	"unsafe"
+
+	"github.com/deferz/go-mapster/internal/cache"
+)
+
+// structLayout describes the members of a struct pair that are copied as memory
+// instead of being mapped one by one. Only fields of identical types holding no
+// pointers other than strings qualify, as mapping them would assign them as they are.
+type structLayout struct {
+	identical bool              // same fields, types and order: the struct is copied as a whole
+	ranges    []byteRange       // runs of pointer-free fields at matching offsets
+	direct    []directField     // fields holding strings, or all fields of identical layouts, assigned as they are
+	mapped    []cache.FieldInfo // remaining target fields, mapped one by one
+}
+
+// byteRange is a run of target memory copied from the source
+type byteRange struct {
+	srcOffset uintptr
+	dstOffset uintptr
+	size      uintptr
+}
+
+// directField is a target field assigned from the source field at the same name
+type directField struct {
+	src int
+	dst int
+}
+
+// copiesLayout reports whether the current options leave plain fields of identical types
+// assigned as they are, so that they can be copied as memory
+func (s *state) copiesLayout(members map[string]MemberFunc) bool {
+	return members == nil && s.mask == nil && !s.merging() &&
+		s.opts.Maps != MapDeepMerge && s.opts.Collection == CollectionReplace &&
+		s.opts.MaxDepth <= 0 && s.opts.MaxCollectionLen <= 0 && s.opts.MaxElements <= 0
+}
+
+// copyLayout copies the plain fields of src to dst and returns the target fields left to map,
+// or all target fields when the pair has no copyable fields
+func copyLayout(src, dst reflect.Value, layout *structLayout, dstTypeInfo *cache.TypeInfo) []cache.FieldInfo {
+	if layout == nil {
+		return dstTypeInfo.Fields
+	}
+
+	if layout.identical && src.CanAddr() {
+		// Reinterpret the source memory as the target type for a single typed copy
+		dst.Set(reflect.NewAt(dst.Type(), unsafe.Pointer(src.UnsafeAddr())).Elem())
+		return nil
+	}
+	if layout.identical {
+		// Without the source memory, assign the fields one by one
+		for _, field := range layout.direct {
+			dst.Field(field.dst).Set(src.Field(field.src))
+		}
+		return nil
+	}
+
+	// Raw copies need the source memory
+	if !src.CanAddr() {
+		return dstTypeInfo.Fields
+	}
+
+	srcBase := unsafe.Pointer(src.UnsafeAddr())
+	dstBase := unsafe.Pointer(dst.UnsafeAddr())
+	for _, r := range layout.ranges {
+		copy(unsafe.Slice((*byte)(unsafe.Add(dstBase, r.dstOffset)), r.size),
+			unsafe.Slice((*byte)(unsafe.Add(srcBase, r.srcOffset)), r.size))
+	}
+	for _, field := range layout.direct {
+		dst.Field(field.dst).Set(src.Field(field.src))
+	}
+	return layout.mapped
+}
+
+// buildLayout finds the target fields read from a source field of the same name and identical plain type,
+// or returns nil when no field can be copied. Layouts are compiled with the struct plan of the pair.
+func buildLayout(srcType, dstType reflect.Type) *structLayout {
+	dstTypeInfo := cache.GetGlobalCache().GetOrCreate(dstType)
+	if sameLayout(srcType, dstType) {
+		layout := &structLayout{identical: true}
+		for i := 0; i < dstType.NumField(); i++ {
+			layout.direct = append(layout.direct, directField{src: i, dst: i})
+		}
+		return layout
+	}
+
+	layout := &structLayout{}
+	var last *byteRange
+	lastSrc, lastDst := -2, -2
+
+	for _, fieldInfo := range dstTypeInfo.Fields {
+		srcField, ok := srcType.FieldByName(fieldInfo.Name)
+		if !ok || len(srcField.Index) != 1 || srcField.PkgPath != "" || !copiesField(srcField.Type, fieldInfo) {
+			layout.mapped = append(layout.mapped, fieldInfo)
+			continue
+		}
+
+		srcIndex := srcField.Index[0]
+		if !pointerFree(fieldInfo.Type) {
+			layout.direct = append(layout.direct, directField{src: srcIndex, dst: fieldInfo.Index})
+			continue
+		}
+
+		// Extend the previous run when both fields follow it at the same distance
+		dstField := dstType.Field(fieldInfo.Index)
+		if last != nil && srcIndex == lastSrc+1 && fieldInfo.Index == lastDst+1 &&
+			srcField.Offset-last.srcOffset == dstField.Offset-last.dstOffset {
+			last.size = dstField.Offset + dstField.Type.Size() - last.dstOffset
+		} else {
+			layout.ranges = append(layout.ranges, byteRange{
+				srcOffset: srcField.Offset,
+				dstOffset: dstField.Offset,
+				size:      dstField.Type.Size(),
+			})
+			last = &layout.ranges[len(layout.ranges)-1]
+		}
+		lastSrc, lastDst = srcIndex, fieldInfo.Index
+	}
+
+	if len(layout.ranges) == 0 && len(layout.direct) == 0 {
+		return nil
+	}
+	return layout
+}
+
+// sameLayout reports whether two struct types have the same fields in the same order,
+// all exported, untagged and of plain types, so that one converts to the other
+func sameLayout(srcType, dstType reflect.Type) bool {
+	if srcType.NumField() != dstType.NumField() || srcType.Size() != dstType.Size() {
+		return false
+	}
+
+	for i := 0; i < dstType.NumField(); i++ {
+		srcField, dstField := srcType.Field(i), dstType.Field(i)
+		if srcField.Name != dstField.Name || srcField.Type != dstField.Type ||
+			srcField.Anonymous != dstField.Anonymous || srcField.PkgPath != "" || dstField.PkgPath != "" {
+			return false
+		}
+		if _, tagged := srcField.Tag.Lookup(cache.TagName); tagged {
+			return false
+		}
+		if _, tagged := dstField.Tag.Lookup(cache.TagName); tagged {
+			return false
+		}
+		if !plainType(dstField.Type) {
+			return false
+		}
+	}
+	return true
+}
+
+// copiesField reports whether a target field is assigned as it is from a source field of the given type
+func copiesField(srcType reflect.Type, fieldInfo cache.FieldInfo) bool {
+	return srcType == fieldInfo.Type && fieldInfo.Tag == (cache.FieldTag{}) && plainType(fieldInfo.Type)
+}
+
+// plainType reports whether values of the type hold no pointers other than strings
+// and are assigned as they are when mapped to the same type
+func plainType(t reflect.Type) bool {
+	if GetGlobalRegistry().Customizes(t, t) {
+		return false
+	}
+
+	switch t.Kind() {
+	case reflect.Bool, reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64, reflect.Complex64, reflect.Complex128:
+		return true
+	case reflect.Array:
+		return plainType(t.Elem())
+	case reflect.Struct:
+		// Structs with tag options are mapped field by field even to the same type
+		if cache.GetGlobalCache().GetOrCreate(t).HasTagOptions {
+			return false
+		}
+		for i := 0; i < t.NumField(); i++ {
+			if !plainType(t.Field(i).Type) {
+				return false
+			}
+		}
+		return true
+	default:
+		return false
+	}
+}
+
+// pointerFree reports whether values of a plain type can be copied as raw memory
+func pointerFree(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.String:
+		return false
+	case reflect.Array:
+		return pointerFree(t.Elem())
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			if !pointerFree(t.Field(i).Type) {
+				return false
+			}
+		}
+		return true
+	default:
+		return true
+	}
+}
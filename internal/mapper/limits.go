@@ -0,0 +1,82 @@
+package mapper
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// LimitKind names a mapping limit
+type LimitKind string
+
+const (
+	// LimitDepth is the maximum nesting of structs, collections, maps and pointers
+	LimitDepth LimitKind = "depth"
+	// LimitCollectionLen is the maximum length of a single source collection or map
+	LimitCollectionLen LimitKind = "collection length"
+	// LimitElements is the maximum number of collection and map elements mapped per call
+	LimitElements LimitKind = "elements"
+)
+
+// LimitError reports that a mapping exceeded a configured limit
+type LimitError struct {
+	Kind LimitKind // limit that was exceeded
+	Max  int       // configured maximum
+	Path string    // target path where the limit was exceeded, empty for the root value
+}
+
+// Error implements the error interface
+func (e *LimitError) Error() string {
+	return fmt.Sprintf("%s limit of %d exceeded at %s", e.Kind, e.Max, describePath(e.Path))
+}
+
+// Unwrap returns ErrLimitExceeded
+func (e *LimitError) Unwrap() error {
+	return ErrLimitExceeded
+}
+
+// limitsNesting reports whether limits are configured and apply to values of the target kind
+func (s *state) limitsNesting(dst reflect.Value) bool {
+	if s.opts.MaxDepth <= 0 && s.opts.MaxCollectionLen <= 0 && s.opts.MaxElements <= 0 {
+		return false
+	}
+
+	switch dst.Kind() {
+	case reflect.Struct, reflect.Slice, reflect.Array, reflect.Map, reflect.Ptr:
+		return true
+	default:
+		return false
+	}
+}
+
+// enterLevel enters a nested value, enforcing the depth limit and counting the source's elements
+func (s *state) enterLevel(src reflect.Value, dstType reflect.Type) error {
+	if s.opts.MaxDepth > 0 && s.depth >= s.opts.MaxDepth {
+		return s.limitError(LimitDepth, s.opts.MaxDepth, src.Type(), dstType)
+	}
+
+	switch src.Kind() {
+	case reflect.Slice, reflect.Array, reflect.Map:
+		n := src.Len()
+		if s.opts.MaxCollectionLen > 0 && n > s.opts.MaxCollectionLen {
+			return s.limitError(LimitCollectionLen, s.opts.MaxCollectionLen, src.Type(), dstType)
+		}
+		s.elements += n
+		if s.opts.MaxElements > 0 && s.elements > s.opts.MaxElements {
+			return s.limitError(LimitElements, s.opts.MaxElements, src.Type(), dstType)
+		}
+	}
+
+	s.depth++
+	return nil
+}
+
+// leaveLevel leaves a nested value entered with enterLevel
+func (s *state) leaveLevel() {
+	s.depth--
+}
+
+// limitError builds the error for an exceeded limit at the current path
+func (s *state) limitError(kind LimitKind, max int, srcType, dstType reflect.Type) error {
+	path := formatPath(s.path)
+	return s.mappingError(&LimitError{Kind: kind, Max: max, Path: path}, srcType, dstType)
+}
@@ -0,0 +1,122 @@
+package mapper
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/deferz/go-mapster/internal/cache"
+)
+
+// fieldMask is a compiled field mask: the selected members of a struct by field name.
+// A member with a nil mask is selected together with all of its descendants.
+type fieldMask map[string]fieldMask
+
+// compileFieldMask compiles dotted destination paths against the target type.
+// Path segments match field names case-insensitively and ignoring underscores,
+// so "address.postal_code" selects Address.PostalCode. Unknown paths are reported as errors.
+func compileFieldMask(dstType reflect.Type, paths []string) (fieldMask, error) {
+	mask := fieldMask{}
+	for _, path := range paths {
+		if err := mask.add(dstType, path, strings.Split(path, ".")); err != nil {
+			return nil, err
+		}
+	}
+	return mask, nil
+}
+
+// add selects the member path given by segments within typ
+func (m fieldMask) add(typ reflect.Type, path string, segments []string) error {
+	typ = maskElem(typ)
+	chain, ok := findMaskMember(typ, segments[0])
+	if !ok {
+		return fmt.Errorf("unknown field mask path %q: %w %q in %s", path, ErrUnmappedMember, segments[0], typ)
+	}
+
+	// Members promoted from embedded structs are selected through the embedded field
+	node := m
+	for i, member := range chain {
+		last := i == len(chain)-1
+		child, exists := node[member.Name]
+		switch {
+		case exists && child == nil:
+			// Already selected as a whole, only validate the rest of the path
+			child = fieldMask{}
+		case last && len(segments) == 1:
+			node[member.Name] = nil
+			return nil
+		case !exists:
+			child = fieldMask{}
+			node[member.Name] = child
+		}
+		node = child
+	}
+
+	if len(segments) == 1 {
+		return nil
+	}
+	return node.add(chain[len(chain)-1].Type, path, segments[1:])
+}
+
+// maskElem returns the struct type reached through pointers, collections and map values
+func maskElem(typ reflect.Type) reflect.Type {
+	for {
+		switch typ.Kind() {
+		case reflect.Ptr, reflect.Slice, reflect.Array, reflect.Map:
+			typ = typ.Elem()
+		default:
+			return typ
+		}
+	}
+}
+
+// findMaskMember finds the member matching a path segment, returning the chain of
+// embedded fields leading to it followed by the member itself
+func findMaskMember(typ reflect.Type, segment string) ([]cache.FieldInfo, bool) {
+	if typ.Kind() != reflect.Struct {
+		return nil, false
+	}
+
+	typeInfo := cache.GetGlobalCache().GetOrCreate(typ)
+	name := normalizeMaskName(segment)
+	for _, fieldInfo := range typeInfo.Fields {
+		if normalizeMaskName(fieldInfo.Name) == name {
+			return []cache.FieldInfo{fieldInfo}, true
+		}
+	}
+	if remain := typeInfo.RemainField; remain != nil && normalizeMaskName(remain.Name) == name {
+		return []cache.FieldInfo{*remain}, true
+	}
+
+	for _, fieldInfo := range typeInfo.Fields {
+		if !fieldInfo.IsAnonymous {
+			continue
+		}
+		if chain, ok := findMaskMember(maskElem(fieldInfo.Type), segment); ok {
+			return append([]cache.FieldInfo{fieldInfo}, chain...), true
+		}
+	}
+
+	return nil, false
+}
+
+// normalizeMaskName folds case and drops underscores for path matching
+func normalizeMaskName(name string) string {
+	return strings.ToLower(strings.ReplaceAll(name, "_", ""))
+}
+
+// selectField reports whether the field mask selects the named target field and
+// narrows the mask to the field's descendants. The caller restores the returned mask.
+func (s *state) selectField(name string) (fieldMask, bool) {
+	saved := s.mask
+	if saved == nil {
+		return nil, true
+	}
+
+	sub, selected := saved[name]
+	if !selected {
+		return saved, false
+	}
+	s.mask = sub
+	return saved, true
+}
@@ -0,0 +1,231 @@
+package mapper
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/deferz/go-mapster/internal/cache"
+)
+
+// Options controls optional mapping behaviour.
+// Options are set per call and can be overridden for a registered pair.
+type Options struct {
+	// WeaklyTyped enables lenient conversions for loosely typed sources,
+	// such as JSON-decoded maps or configuration values
+	WeaklyTyped bool
+
+	// KeyField is the dotted path of the element member used as map key
+	// when mapping a slice to a map
+	KeyField string
+	// KeyFunc selects the map key of a slice element, taking precedence over KeyField
+	KeyFunc func(elem any) (any, error)
+	// DuplicateKeys decides what happens when two elements produce the same key
+	DuplicateKeys DuplicateKeyPolicy
+	// KeyLess orders map keys when mapping a map to a slice; keys are sorted
+	// in their natural order when nil, and keys without one fail to map
+	KeyLess func(a, b any) bool
+
+	// Collection decides how source collections are combined with existing target slices
+	Collection CollectionStrategy
+	// SyncKey is the dotted path of the identity member used by CollectionSyncByKey
+	SyncKey string
+	// SyncRemoveMissing removes target elements whose key is missing from the source
+	// when using CollectionSyncByKey
+	SyncRemoveMissing bool
+
+	// Maps decides how source maps are combined with existing target maps
+	Maps MapStrategy
+	// MapRemoveMissing removes target keys missing from the source when merging maps
+	MapRemoveMissing bool
+
+	// IgnoreNil skips nil source pointers, interfaces, maps and slices so the target keeps its value
+	IgnoreNil bool
+	// IgnoreZero skips zero source values so the target keeps its value
+	IgnoreZero bool
+
+	// DeepCopy copies pointers, slices, maps, arrays and interface values of identical
+	// types instead of sharing them with the source
+	DeepCopy bool
+	// SharedTypes are shared with the source even when DeepCopy is set
+	SharedTypes []reflect.Type
+
+	// Cycles decides what happens when the source graph refers back to a value being mapped
+	Cycles CyclePolicy
+	// PreserveReferences maps every occurrence of a source pointer to the same target pointer
+	PreserveReferences bool
+
+	// MaxDepth limits the nesting of structs, collections, maps and pointers, 0 for no limit
+	MaxDepth int
+	// MaxCollectionLen limits the length of each source collection or map, 0 for no limit
+	MaxCollectionLen int
+	// MaxElements limits the collection and map elements mapped per call, 0 for no limit
+	MaxElements int
+
+	// CollectErrors continues past field-level failures, leaving those fields untouched,
+	// and reports every failure at the end as MappingErrors
+	CollectErrors bool
+
+	// PropagatePanics lets panics raised while mapping escape instead of
+	// reporting them as a MappingError, for debugging
+	PropagatePanics bool
+
+	// ReuseCapacity lets the replace strategies reuse the backing array of a target
+	// slice with enough capacity and the storage of a target map instead of allocating
+	ReuseCapacity bool
+
+	// Parallel maps the elements of replaced slices and arrays of at least
+	// ParallelThreshold elements concurrently on ParallelWorkers goroutines.
+	// Zero values use defaultParallelThreshold and GOMAXPROCS.
+	Parallel          bool
+	ParallelThreshold int
+	ParallelWorkers   int
+
+	// FieldMask restricts the mapping to these dotted target paths and their descendants.
+	// It is compiled against the call's target type, so it only applies per call.
+	FieldMask []string
+}
+
+// CollectionStrategy decides how a source collection is combined with an existing target slice
+type CollectionStrategy int
+
+const (
+	// CollectionReplace replaces the target with a new collection (default)
+	CollectionReplace CollectionStrategy = iota
+	// CollectionAppend appends the mapped source elements to the existing target elements.
+	// Array targets cannot grow and are replaced.
+	CollectionAppend
+	// CollectionMergeByIndex maps each source element onto the target element at the same index
+	CollectionMergeByIndex
+	// CollectionSyncByKey matches target elements by an identity member, updates matches
+	// in place, adds new elements and optionally removes missing ones.
+	// Array targets cannot grow or shrink and are replaced.
+	CollectionSyncByKey
+)
+
+// DuplicateKeyPolicy decides what happens when two slice elements map to the same key
+type DuplicateKeyPolicy int
+
+const (
+	// DuplicateKeyError fails the mapping on duplicate keys
+	DuplicateKeyError DuplicateKeyPolicy = iota
+	// DuplicateKeyKeepFirst keeps the first element with a given key
+	DuplicateKeyKeepFirst
+	// DuplicateKeyKeepLast keeps the last element with a given key
+	DuplicateKeyKeepLast
+)
+
+// state carries the options and bookkeeping of a single mapping call
+type state struct {
+	ctx     context.Context // context of the mapping call, passed to converters, hooks and resolvers
+	opts    *Options
+	mask    fieldMask     // compiled FieldMask narrowed to the current target, nil when unrestricted
+	path    []pathSegment // target path of the value being mapped
+	srcPath []pathSegment // source path of the value being mapped
+
+	depth    int // nesting of the value being mapped, counted when limits are set
+	elements int // collection and map elements mapped so far, counted when limits are set
+
+	visiting map[refKey]int           // references being mapped, with the path depth where they were entered
+	refs     map[refKey]reflect.Value // target pointers built for source pointers, by target type
+
+	errs MappingErrors // field-level failures collected under CollectErrors
+
+	// Initial storage of the paths, so shallow mappings do not allocate them
+	pathBuf    [pathBufSize]pathSegment
+	srcPathBuf [pathBufSize]pathSegment
+}
+
+// pathBufSize is the path depth stored without allocating
+const pathBufSize = 4
+
+// newState creates the state for a mapping call
+func newState(opts *Options) *state {
+	if opts == nil {
+		opts = &Options{}
+	}
+	s := &state{ctx: context.Background(), opts: opts}
+	s.path = s.pathBuf[:0]
+	s.srcPath = s.srcPathBuf[:0]
+	return s
+}
+
+// MapStrategy decides how a source map is combined with an existing target map
+type MapStrategy int
+
+const (
+	// MapReplace replaces the target with a new map (default)
+	MapReplace MapStrategy = iota
+	// MapMerge keeps existing target keys and overwrites the entries present in the source
+	MapMerge
+	// MapDeepMerge keeps existing target keys and maps source entries onto the existing entries
+	MapDeepMerge
+)
+
+// applyFieldTag switches to the options overridden by a field tag and returns the previous options
+func (s *state) applyFieldTag(tag cache.FieldTag) *Options {
+	saved := s.opts
+
+	opts := *saved
+	switch tag.Collection {
+	case "replace":
+		opts.Collection = CollectionReplace
+		opts.Maps = MapReplace
+	case "append":
+		opts.Collection = CollectionAppend
+	case "mergeindex":
+		opts.Collection = CollectionMergeByIndex
+	case "sync":
+		opts.Collection = CollectionSyncByKey
+		opts.SyncKey = tag.SyncKey
+		opts.SyncRemoveMissing = tag.Prune
+	}
+	switch tag.MapMerge {
+	case "merge":
+		opts.Maps = MapMerge
+		opts.MapRemoveMissing = tag.Prune
+	case "deepmerge":
+		opts.Maps = MapDeepMerge
+		opts.MapRemoveMissing = tag.Prune
+	}
+	if tag.IgnoreNil {
+		opts.IgnoreNil = true
+	}
+	if tag.IgnoreZero {
+		opts.IgnoreZero = true
+	}
+	s.opts = &opts
+
+	return saved
+}
+
+// applyPair switches to the options of a registered pair and returns the previous options
+func (s *state) applyPair(pair *PairConfig) *Options {
+	saved := s.opts
+
+	opts := *saved
+	for _, opt := range pair.Options {
+		opt(&opts)
+	}
+	s.opts = &opts
+
+	return saved
+}
+
+// merging reports whether source values may be skipped, so targets must be merged instead of replaced
+func (s *state) merging() bool {
+	return s.opts.IgnoreNil || s.opts.IgnoreZero
+}
+
+// skips reports whether the source value is skipped by IgnoreNil or IgnoreZero
+func (s *state) skips(src reflect.Value) bool {
+	if s.opts.IgnoreZero && src.IsZero() {
+		return true
+	}
+	if s.opts.IgnoreNil {
+		switch src.Kind() {
+		case reflect.Ptr, reflect.Interface, reflect.Map, reflect.Slice:
+			return src.IsNil()
+		}
+	}
+	return false
+}
@@ -0,0 +1,82 @@
+package mapper
+
+import (
+	"context"
+	"reflect"
+)
+
+// contextCheckInterval is the number of collection elements mapped between cancellation checks
+const contextCheckInterval = 64
+
+// checkContext returns the context's error at every contextCheckInterval-th element of a collection loop
+func (s *state) checkContext(i int) error {
+	if i%contextCheckInterval != 0 {
+		return nil
+	}
+	return s.ctx.Err()
+}
+
+// cancelled reports whether the mapping call's context is done
+func (s *state) cancelled() bool {
+	return s.ctx.Err() != nil
+}
+
+// mapPair maps a value of a registered pair with its options, converter and hooks
+func (s *state) mapPair(pair *PairConfig, src, dst reflect.Value) error {
+	if len(pair.Options) > 0 {
+		saved := s.applyPair(pair)
+		defer func() { s.opts = saved }()
+	}
+
+	if pair.Convert != nil {
+		if err := pair.Convert(s.ctx, src, dst); err != nil {
+			return err
+		}
+	} else if err := s.mapResolved(src, dst); err != nil {
+		return err
+	}
+
+	for _, hook := range pair.AfterMap {
+		if err := hook(s.ctx, src, dst); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// memberResolvers returns the member resolvers registered for a struct pair
+func memberResolvers(srcType, dstType reflect.Type) map[string]MemberFunc {
+	if pair := GetGlobalRegistry().Get(srcType, dstType); pair != nil {
+		return pair.Members
+	}
+	return nil
+}
+
+// resolveMember maps the value returned by a member resolver to the target field.
+// A nil value leaves the field untouched.
+func (s *state) resolveMember(resolve MemberFunc, src, dstField reflect.Value, fieldName string) error {
+	s.pushField(fieldName)
+	defer s.popPath()
+
+	value, err := resolve(s.ctx, src)
+	if err != nil {
+		return s.collectError(s.mappingError(err, src.Type(), dstField.Type()))
+	}
+	if value == nil {
+		return nil
+	}
+
+	if err := s.mapValue(reflect.ValueOf(value), dstField); err != nil {
+		return s.collectError(err)
+	}
+	return nil
+}
+
+// withContext sets the context of the mapping call, using the background context for nil
+func (s *state) withContext(ctx context.Context) *state {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	s.ctx = ctx
+	return s
+}
@@ -0,0 +1,138 @@
+package mapper
+
+import (
+	"reflect"
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+// defaultParallelThreshold is the collection length from which Parallel maps elements concurrently
+const defaultParallelThreshold = 1024
+
+// parallelizes reports whether the n elements of a replaced collection are mapped concurrently.
+// Mappings sharing bookkeeping across elements, preserved references and the total
+// element limit, stay sequential.
+func (s *state) parallelizes(n int) bool {
+	if !s.opts.Parallel || s.opts.PreserveReferences || s.opts.Cycles == CyclePreserve || s.opts.MaxElements > 0 {
+		return false
+	}
+
+	threshold := s.opts.ParallelThreshold
+	if threshold <= 0 {
+		threshold = defaultParallelThreshold
+	}
+	return n >= threshold && n > 1 && s.parallelWorkers() > 1
+}
+
+// parallelWorkers returns the number of goroutines mapping a collection concurrently
+func (s *state) parallelWorkers() int {
+	if s.opts.ParallelWorkers > 0 {
+		return s.opts.ParallelWorkers
+	}
+	return runtime.GOMAXPROCS(0)
+}
+
+// fork creates the state of a worker mapping part of the current collection.
+// Paths and references being mapped are copied, options and the field mask are only read.
+func (s *state) fork() *state {
+	w := *s
+	w.path = append([]pathSegment(nil), s.path...)
+	w.srcPath = append([]pathSegment(nil), s.srcPath...)
+	w.errs = nil
+	if s.visiting != nil {
+		w.visiting = make(map[refKey]int, len(s.visiting))
+		for key, depth := range s.visiting {
+			w.visiting[key] = depth
+		}
+	}
+	return &w
+}
+
+// chunkResult is the outcome of a worker mapping a contiguous range of elements
+type chunkResult struct {
+	err      error
+	errs     MappingErrors
+	panicked bool
+	panicVal any
+}
+
+// mapElementsParallel maps the first n elements of src onto dst, splitting them into
+// contiguous ranges mapped by concurrent workers. Each element only writes its own
+// target index. The error of the lowest failing element is returned, as when mapping
+// sequentially, and errors collected under CollectErrors are kept in element order.
+func (s *state) mapElementsParallel(plan *Plan, src, dst reflect.Value, n int) error {
+	workers := s.parallelWorkers()
+	if workers > n {
+		workers = n
+	}
+	size := (n + workers - 1) / workers
+
+	results := make([]chunkResult, workers)
+	failed := int32(workers) // lowest failed chunk; later chunks stop early
+	var wg sync.WaitGroup
+
+	for w := 0; w < workers; w++ {
+		start, end := w*size, (w+1)*size
+		if end > n {
+			end = n
+		}
+
+		wg.Add(1)
+		go func(w, start, end int) {
+			defer wg.Done()
+			ws := s.fork()
+			result := &results[w]
+			defer func() { result.errs = ws.errs }()
+
+			// Hand panics escaping a worker back to the calling goroutine
+			if s.opts.PropagatePanics {
+				defer func() {
+					if r := recover(); r != nil {
+						result.panicked, result.panicVal = true, r
+						markFailed(&failed, w)
+					}
+				}()
+			}
+
+			for i := start; i < end; i++ {
+				if atomic.LoadInt32(&failed) < int32(w) {
+					return
+				}
+				if err := ws.checkContext(i - start); err != nil {
+					result.err = err
+					markFailed(&failed, w)
+					return
+				}
+
+				if err := ws.mapAt(plan, indexSegment(i), indexSegment(i), src.Index(i), dst.Index(i)); err != nil {
+					result.err = err
+					markFailed(&failed, w)
+					return
+				}
+			}
+		}(w, start, end)
+	}
+	wg.Wait()
+
+	for _, result := range results {
+		s.errs = append(s.errs, result.errs...)
+		if result.panicked {
+			panic(result.panicVal)
+		}
+		if result.err != nil {
+			return result.err
+		}
+	}
+	return nil
+}
+
+// markFailed lowers the failed chunk index to w
+func markFailed(failed *int32, w int) {
+	for {
+		current := atomic.LoadInt32(failed)
+		if int32(w) >= current || atomic.CompareAndSwapInt32(failed, current, int32(w)) {
+			return
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package mapper
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// pathSegment is one step of the path to a value being mapped:
+// a struct field, a map entry, a field holding a map entry, or a slice or array element
+type pathSegment struct {
+	name  string        // struct field name
+	key   reflect.Value // map key, invalid for other segments
+	index int           // slice or array index, used when name and key are empty
+}
+
+// fieldSegment returns the segment of a struct field
+func fieldSegment(name string) pathSegment {
+	return pathSegment{name: name}
+}
+
+// indexSegment returns the segment of a slice or array element
+func indexSegment(index int) pathSegment {
+	return pathSegment{index: index}
+}
+
+// keySegment returns the segment of a map entry
+func keySegment(key reflect.Value) pathSegment {
+	return pathSegment{key: key}
+}
+
+// pushPath enters a target member and the source member mapped to it
+func (s *state) pushPath(dst, src pathSegment) {
+	s.path = append(s.path, dst)
+	s.srcPath = append(s.srcPath, src)
+}
+
+// pushField enters a struct field with the same name in the source and the target
+func (s *state) pushField(name string) {
+	s.pushPath(fieldSegment(name), fieldSegment(name))
+}
+
+// pushIndex enters an element with the same index in the source and the target
+func (s *state) pushIndex(index int) {
+	s.pushPath(indexSegment(index), indexSegment(index))
+}
+
+// pushKey enters a map entry with the same key in the source and the target
+func (s *state) pushKey(key reflect.Value) {
+	s.pushPath(keySegment(key), keySegment(key))
+}
+
+// popPath leaves the innermost target and source members
+func (s *state) popPath() {
+	s.path = s.path[:len(s.path)-1]
+	s.srcPath = s.srcPath[:len(s.srcPath)-1]
+}
+
+// mapAt maps src onto dst within a target member and the source member mapped to it,
+// with the compiled plan of the pair when given. The members are left even when mapping panics.
+func (s *state) mapAt(plan *Plan, dstSegment, srcSegment pathSegment, src, dst reflect.Value) error {
+	s.pushPath(dstSegment, srcSegment)
+	defer s.popPath()
+	return s.mapPlanned(plan, src, dst)
+}
+
+// formatPath formats path segments as "Items[2].Attrs[color]", or "" for the root value
+func formatPath(path []pathSegment) string {
+	var b strings.Builder
+	for _, segment := range path {
+		if segment.name != "" {
+			if b.Len() > 0 {
+				b.WriteByte('.')
+			}
+			b.WriteString(segment.name)
+		}
+		switch {
+		case segment.key.IsValid():
+			fmt.Fprintf(&b, "[%v]", segment.key)
+		case segment.name == "":
+			fmt.Fprintf(&b, "[%d]", segment.index)
+		}
+	}
+	return b.String()
+}
+
+// describePath returns a formatted path for messages, naming the root value
+func describePath(path string) string {
+	if path == "" {
+		return "(root)"
+	}
+	return path
+}
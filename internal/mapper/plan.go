@@ -0,0 +1,214 @@
+package mapper
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/deferz/go-mapster/internal/cache"
+)
+
+// Plan is the compiled mapping of a source type to a target type. The decisions depending
+// only on the types and the registered pairs are taken once and reused for every value and
+// element of the pair: the registered pair, the derived pair of polymorphic targets, whether
+// values are assigned as they are, the struct plan and the primitive element kernel.
+// Plans are cached with the registered pairs, so registering a pair compiles them again.
+type Plan struct {
+	Src reflect.Type
+	Dst reflect.Type
+
+	resolved bool          // source values need no interface, pointer or reference resolution
+	assigns  bool          // identical types holding no customized values
+	derived  *PairConfig   // derived pair of a polymorphic target
+	base     *PairConfig   // base pair of a polymorphic target
+	pair     *PairConfig   // registered pair customizing the mapping
+	structs  *structPlan   // struct pairs: fields, member resolvers and layout
+	kernel   elementKernel // primitive pairs: bulk conversion of elements
+}
+
+// CompilePlan returns the plan mapping src to dst, compiling it on first use
+func CompilePlan(src, dst reflect.Type) *Plan {
+	plans := &GetGlobalRegistry().load().plans
+	key := PairKey{Src: src, Dst: dst}
+	if plan, ok := plans.Load(key); ok {
+		return plan.(*Plan)
+	}
+
+	cache.GetGlobalCache().RegisterMapping(src, dst)
+	plan, _ := plans.LoadOrStore(key, compilePlan(src, dst))
+	return plan.(*Plan)
+}
+
+// compilePlan takes the decisions mapDispatch and mapResolved take for values of the pair
+func compilePlan(src, dst reflect.Type) *Plan {
+	registry := GetGlobalRegistry()
+	plan := &Plan{Src: src, Dst: dst}
+
+	// Interfaces, pointers, maps and slices are resolved or tracked per value
+	switch src.Kind() {
+	case reflect.Interface, reflect.Ptr, reflect.Map, reflect.Slice:
+		return plan
+	}
+	plan.resolved = true
+
+	plan.assigns = src == dst && !registry.holdsCustomized(src)
+	if dst.Kind() == reflect.Interface {
+		plan.derived, plan.base = registry.FindDerived(src, dst)
+	}
+	if pair := registry.Get(src, dst); pair != nil && pair.customizes() {
+		plan.pair = pair
+	}
+
+	if src.Kind() == reflect.Struct && dst.Kind() == reflect.Struct {
+		plan.structs = compileStruct(src, dst)
+	} else if src != dst {
+		plan.kernel = compileKernel(src, dst)
+	}
+	return plan
+}
+
+// MapValue maps a source value to a target value of the plan's types
+func (p *Plan) MapValue(ctx context.Context, src, dst reflect.Value, opts *Options) error {
+	s, err := newCallState(ctx, p.Dst, opts)
+	if err != nil {
+		return err
+	}
+	return s.finish(s.mapPlanned(p, src, dst))
+}
+
+// MapElements maps each element of a source slice onto the element at the same index
+// of a target slice of the same length. Field masks apply to each element.
+func (p *Plan) MapElements(ctx context.Context, src, dst reflect.Value, opts *Options) error {
+	s, err := newCallState(ctx, p.Dst, opts)
+	if err != nil {
+		return err
+	}
+	return s.finish(s.mapRoot(src, dst, func() error {
+		n := src.Len()
+		if handled, err := p.copyElements(s, src, dst, n); handled {
+			return err
+		}
+		return s.mapEachElement(p, src, dst, n)
+	}))
+}
+
+// MapValues maps each value of a source map to a target value stored under the same key
+// in the target map. Field masks apply to each value.
+func (p *Plan) MapValues(ctx context.Context, src, dst reflect.Value, opts *Options) error {
+	s, err := newCallState(ctx, p.Dst, opts)
+	if err != nil {
+		return err
+	}
+	return s.finish(s.mapRoot(src, dst, func() error {
+		iter := src.MapRange()
+		for i := 0; iter.Next(); i++ {
+			if err := s.checkContext(i); err != nil {
+				return err
+			}
+
+			value := reflect.New(p.Dst).Elem()
+			if err := s.mapAt(p, keySegment(iter.Key()), keySegment(iter.Key()), iter.Value(), value); err != nil {
+				return err
+			}
+			dst.SetMapIndex(iter.Key(), value)
+		}
+		return nil
+	}))
+}
+
+// ElementMapper maps the elements of a stream one at a time, sharing the options
+// and context of a single mapping call. Each element is mapped on its own: limits,
+// cycle detection and preserved references do not carry over between elements.
+type ElementMapper struct {
+	s    *state
+	plan *Plan
+}
+
+// ElementMapper starts a mapping call for the elements of a stream
+func (p *Plan) ElementMapper(ctx context.Context, opts *Options) (*ElementMapper, error) {
+	s, err := newCallState(ctx, p.Dst, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &ElementMapper{s: s, plan: p}, nil
+}
+
+// Map maps the element at index i of the stream, reporting failures at paths starting with the index
+func (m *ElementMapper) Map(i int, src, dst reflect.Value) error {
+	s := m.s
+	s.depth, s.elements = 0, 0
+	s.visiting, s.refs, s.errs = nil, nil, nil
+	return s.finish(s.mapAt(m.plan, indexSegment(i), indexSegment(i), src, dst))
+}
+
+// copyElements copies primitive elements in bulk, converting them with the kernel of the pair
+func (p *Plan) copyElements(s *state, src, dst reflect.Value, n int) (bool, error) {
+	if p.Src == p.Dst {
+		return s.copyElements(src, dst, n)
+	}
+	return s.convertElements(p.kernel, src, dst, n)
+}
+
+// mapPlanned maps a value with the decisions compiled in the plan of its pair, reporting
+// failures and panics as mapValue does. Values the plan does not resolve, and values
+// counted against the limits, are dispatched by mapValue.
+func (s *state) mapPlanned(p *Plan, src, dst reflect.Value) (err error) {
+	if p == nil || !p.resolved || !src.IsValid() || src.Type() != p.Src || !dst.CanSet() || dst.Type() != p.Dst || s.limitsNesting(dst) {
+		return s.mapValue(src, dst)
+	}
+
+	if !s.opts.PropagatePanics {
+		defer s.recoverPanic(src, dst, &err)
+	}
+
+	if err = s.mapCompiled(p, src, dst); err != nil {
+		return s.mappingError(err, p.Src, p.Dst)
+	}
+	return nil
+}
+
+// mapCompiled maps a value following the decisions of mapDispatch and mapResolved compiled in the plan
+func (s *state) mapCompiled(p *Plan, src, dst reflect.Value) error {
+	// Keep the target value when the source is absent in a partial update
+	if s.skips(src) {
+		return nil
+	}
+
+	if p.assigns && s.assignsDirectly(dst) {
+		if s.opts.DeepCopy {
+			return s.deepCopy(src, dst)
+		}
+		dst.Set(src)
+		return nil
+	}
+
+	if p.base != nil {
+		return s.mapDerived(src, dst, p.derived, p.base)
+	}
+	if p.pair != nil {
+		return s.mapPair(p.pair, src, dst)
+	}
+
+	// Struct pairs map their fields directly, other values choose their strategy
+	if p.structs != nil {
+		return s.mapCompiledStruct(p.structs, src, dst)
+	}
+	return s.mapResolved(src, dst)
+}
+
+// mapRoot maps the members of a root collection with mapMembers, enforcing the limits
+// and reporting failures as when mapping the collection itself
+func (s *state) mapRoot(src, dst reflect.Value, mapMembers func() error) (err error) {
+	defer func() {
+		if err != nil {
+			err = s.mappingError(err, src.Type(), dst.Type())
+		}
+	}()
+
+	if s.limitsNesting(dst) {
+		if err := s.enterLevel(src, dst.Type()); err != nil {
+			return err
+		}
+		defer s.leaveLevel()
+	}
+	return mapMembers()
+}
@@ -0,0 +1,31 @@
+package mapper
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// mapDerived maps a source value into a polymorphic target using the derived pair
+// registered for the source's dynamic type
+func (s *state) mapDerived(src, dst reflect.Value, derived, base *PairConfig) error {
+	if derived == nil {
+		return fmt.Errorf("%w: no derived mapping for %s in %s -> %s", ErrUnconvertible, src.Type(), base.Src, base.Dst)
+	}
+
+	// The derived pair may be declared on the value type of a pointer source
+	if src.Type() != derived.Src {
+		if src.IsNil() {
+			return nil // Skip mapping for nil pointers
+		}
+		src = src.Elem()
+	}
+
+	// Build the derived target and assign it through the base target type
+	dstValue := reflect.New(derived.Dst).Elem()
+	if err := s.mapValue(src, dstValue); err != nil {
+		return err
+	}
+
+	dst.Set(dstValue)
+	return nil
+}
@@ -0,0 +1,211 @@
+package mapper
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"sync/atomic"
+)
+
+// PairKey identifies a mapping between a source type and a target type
+type PairKey struct {
+	Src reflect.Type
+	Dst reflect.Type
+}
+
+// ConvertFunc maps a source value of a pair's source type onto a target value of its target type
+type ConvertFunc func(ctx context.Context, src, dst reflect.Value) error
+
+// HookFunc runs after a value of a pair has been mapped
+type HookFunc func(ctx context.Context, src, dst reflect.Value) error
+
+// MemberFunc resolves the value of a target struct member from the source struct
+type MemberFunc func(ctx context.Context, src reflect.Value) (any, error)
+
+// PairConfig stores the configuration registered for a source/target type pair
+type PairConfig struct {
+	Src reflect.Type
+	Dst reflect.Type
+	// Derived pairs used to dispatch on the dynamic type of the source
+	Derived []*PairConfig
+	// Options applied while mapping this pair, on top of the call options
+	Options []func(*Options)
+	// Convert replaces the mapping of this pair when set
+	Convert ConvertFunc
+	// AfterMap hooks run in order once a value of this pair has been mapped
+	AfterMap []HookFunc
+	// Members resolve target struct members by name instead of reading the source member
+	Members map[string]MemberFunc
+}
+
+// customizes reports whether the pair changes how its values are mapped as a whole
+func (p *PairConfig) customizes() bool {
+	return len(p.Options) > 0 || p.Convert != nil || len(p.AfterMap) > 0
+}
+
+// registrySnapshot is an immutable view of the registered pairs
+type registrySnapshot struct {
+	pairs map[PairKey]*PairConfig
+	// Pairs with derived mappings, indexed by target type
+	bases map[reflect.Type][]*PairConfig
+	// Whether a pair from a type to itself customizes its values
+	sameType bool
+
+	// Decisions depending on the registered pairs, dropped with the snapshot on registration
+	customized sync.Map // reflect.Type -> bool
+	structs    sync.Map // PairKey -> *structPlan
+	plans      sync.Map // PairKey -> *Plan
+}
+
+// Registry stores the configured type pairs.
+// Lookups happen on every mapped value, so reads use an immutable snapshot
+// that is replaced on registration instead of taking a lock.
+type Registry struct {
+	snapshot atomic.Value // *registrySnapshot
+	mutex    sync.Mutex
+}
+
+// NewRegistry creates a new Registry instance
+func NewRegistry() *Registry {
+	r := &Registry{}
+	r.snapshot.Store(&registrySnapshot{
+		pairs: make(map[PairKey]*PairConfig),
+		bases: make(map[reflect.Type][]*PairConfig),
+	})
+	return r
+}
+
+// load returns the current snapshot
+func (r *Registry) load() *registrySnapshot {
+	return r.snapshot.Load().(*registrySnapshot)
+}
+
+// Register stores the pair configuration, replacing any previous one for the same pair
+func (r *Registry) Register(pair *PairConfig) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	old := r.load()
+	next := &registrySnapshot{
+		pairs: make(map[PairKey]*PairConfig, len(old.pairs)+1),
+		bases: make(map[reflect.Type][]*PairConfig, len(old.bases)+1),
+	}
+
+	key := PairKey{Src: pair.Src, Dst: pair.Dst}
+	for k, v := range old.pairs {
+		next.pairs[k] = v
+	}
+	next.pairs[key] = pair
+	for k, v := range next.pairs {
+		if k.Src == k.Dst && v.customizes() {
+			next.sameType = true
+		}
+	}
+
+	// Rebuild the base index without the replaced pair
+	for dst, bases := range old.bases {
+		for _, base := range bases {
+			if (PairKey{Src: base.Src, Dst: base.Dst}) != key {
+				next.bases[dst] = append(next.bases[dst], base)
+			}
+		}
+	}
+	if len(pair.Derived) > 0 {
+		next.bases[pair.Dst] = append(next.bases[pair.Dst], pair)
+	}
+
+	r.snapshot.Store(next)
+}
+
+// Get returns the configuration registered for the pair or nil if not found
+func (r *Registry) Get(src, dst reflect.Type) *PairConfig {
+	snapshot := r.load()
+	if len(snapshot.pairs) == 0 {
+		return nil
+	}
+	return snapshot.pairs[PairKey{Src: src, Dst: dst}]
+}
+
+// Customizes reports whether the pair registered for the types changes how their values are mapped
+func (r *Registry) Customizes(src, dst reflect.Type) bool {
+	pair := r.Get(src, dst)
+	return pair != nil && pair.customizes()
+}
+
+// holdsCustomized reports whether values of the type are, or hold, values of a type whose pair
+// to itself customizes them, so that they cannot be assigned as they are
+func (r *Registry) holdsCustomized(typ reflect.Type) bool {
+	snapshot := r.load()
+	if !snapshot.sameType {
+		return false
+	}
+	if cached, ok := snapshot.customized.Load(typ); ok {
+		return cached.(bool)
+	}
+
+	result := snapshot.reachesCustomized(typ, make(map[reflect.Type]bool))
+	snapshot.customized.Store(typ, result)
+	return result
+}
+
+// reachesCustomized walks the types held by values of typ looking for a customized pair to itself
+func (snapshot *registrySnapshot) reachesCustomized(typ reflect.Type, seen map[reflect.Type]bool) bool {
+	if seen[typ] {
+		return false
+	}
+	seen[typ] = true
+
+	if pair := snapshot.pairs[PairKey{Src: typ, Dst: typ}]; pair != nil && pair.customizes() {
+		return true
+	}
+
+	switch typ.Kind() {
+	case reflect.Ptr, reflect.Slice, reflect.Array:
+		return snapshot.reachesCustomized(typ.Elem(), seen)
+	case reflect.Map:
+		return snapshot.reachesCustomized(typ.Key(), seen) || snapshot.reachesCustomized(typ.Elem(), seen)
+	case reflect.Struct:
+		for i := 0; i < typ.NumField(); i++ {
+			if snapshot.reachesCustomized(typ.Field(i).Type, seen) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// FindDerived looks up the derived pair matching the concrete source type
+// among the base pairs registered for the target type.
+// The returned base is nil when no polymorphic pair targets dst.
+func (r *Registry) FindDerived(src, dst reflect.Type) (derived, base *PairConfig) {
+	for _, candidate := range r.load().bases[dst] {
+		if candidate.Src.Kind() == reflect.Interface && !src.Implements(candidate.Src) {
+			continue
+		}
+		base = candidate
+
+		for _, pair := range candidate.Derived {
+			if pair.Src == src {
+				return pair, base
+			}
+		}
+		// A derived pair declared on the value type also matches pointers to it
+		if src.Kind() == reflect.Ptr {
+			for _, pair := range candidate.Derived {
+				if pair.Src == src.Elem() {
+					return pair, base
+				}
+			}
+		}
+	}
+
+	return nil, base
+}
+
+// Global shared registry instance
+var globalRegistry = NewRegistry()
+
+// GetGlobalRegistry returns the global registry instance
+func GetGlobalRegistry() *Registry {
+	return globalRegistry
+}
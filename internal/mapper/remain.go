@@ -0,0 +1,142 @@
+package mapper
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/deferz/go-mapster/internal/cache"
+)
+
+// mapRemainingFields collects the source struct members that no target member
+// consumes into the target's remain field
+func (s *state) mapRemainingFields(src, dst reflect.Value, srcTypeInfo, dstTypeInfo *cache.TypeInfo) error {
+	// Only encode the members left over, consumed members are mapped by the target fields
+	leftover := reflect.MakeMap(anyMapType)
+	unconsumed := func(name string) bool {
+		return !consumesSourceMember(srcTypeInfo, dstTypeInfo, name)
+	}
+	if err := s.writeStructFields(src, leftover, unconsumed); err != nil {
+		return err
+	}
+
+	return s.setRemain(dst, dstTypeInfo, leftover)
+}
+
+// consumesSourceMember reports whether a member of the target struct consumes the named source member,
+// either by name, through embedded fields or through nested flattening
+func consumesSourceMember(srcTypeInfo, dstTypeInfo *cache.TypeInfo, name string) bool {
+	if _, exists := dstTypeInfo.FieldsMap[name]; exists {
+		return true
+	}
+	if _, exists := dstTypeInfo.EmbeddedFieldsMap[name]; exists {
+		return true
+	}
+
+	// A nested source struct is consumed when a flattened target field reads from it
+	for _, fieldInfo := range dstTypeInfo.Fields {
+		if root := nestedRoot(srcTypeInfo, fieldInfo.Name); root == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// nestedRoot returns the top-level source field a flattened target field name reads from
+func nestedRoot(srcTypeInfo *cache.TypeInfo, fieldName string) string {
+	if _, exists := srcTypeInfo.FieldsMap[fieldName]; exists {
+		return ""
+	}
+	if nestedFieldInfo, exists := srcTypeInfo.NestedFieldsMap[fieldName]; exists {
+		return nestedFieldInfo.NestedPath[0]
+	}
+	if i := strings.IndexAny(fieldName, "_."); i > 0 {
+		return fieldName[:i]
+	}
+	for _, nestedFieldInfo := range srcTypeInfo.NestedFieldsMap {
+		if nestedFieldInfo.Field.Name == fieldName {
+			return nestedFieldInfo.NestedPath[0]
+		}
+	}
+	return ""
+}
+
+// mapRemainingKeys collects the source map entries whose keys were not consumed into the target's remain field
+func (s *state) mapRemainingKeys(src, dst reflect.Value, dstTypeInfo *cache.TypeInfo, consumed map[string]bool) error {
+	leftover := reflect.MakeMap(reflect.MapOf(src.Type().Key(), src.Type().Elem()))
+	for _, key := range src.MapKeys() {
+		if !consumed[key.String()] {
+			leftover.SetMapIndex(key, src.MapIndex(key))
+		}
+	}
+
+	return s.setRemain(dst, dstTypeInfo, leftover)
+}
+
+// setRemain stores the leftover members into the remain field of the target struct.
+// The remain field is left untouched when there is nothing to collect.
+func (s *state) setRemain(dst reflect.Value, dstTypeInfo *cache.TypeInfo, leftover reflect.Value) error {
+	if leftover.Len() == 0 {
+		return nil
+	}
+
+	remainInfo := dstTypeInfo.RemainField
+	savedMask, selected := s.selectField(remainInfo.Name)
+	if !selected {
+		return nil
+	}
+	defer func() { s.mask = savedMask }()
+
+	s.pushField(remainInfo.Name)
+	defer s.popPath()
+
+	remain := reflect.New(remainInfo.Type).Elem()
+	if err := s.mapValue(leftover, remain); err != nil {
+		return err
+	}
+
+	dst.Field(remainInfo.Index).Set(remain)
+	return nil
+}
+
+// findRemainValue looks up a member in the remain field of the source struct
+func findRemainValue(src reflect.Value, srcTypeInfo *cache.TypeInfo, fieldName string) (reflect.Value, bool) {
+	if srcTypeInfo.RemainField == nil {
+		return reflect.Value{}, false
+	}
+
+	remain := src.Field(srcTypeInfo.RemainField.Index)
+	if remain.IsNil() {
+		return reflect.Value{}, false
+	}
+
+	value := remain.MapIndex(reflect.ValueOf(fieldName).Convert(remain.Type().Key()))
+	return value, value.IsValid()
+}
+
+// spreadRemain writes the entries of the source struct's remain field into a map with string keys,
+// restricted to the keys accepted by include when it is not nil
+func (s *state) spreadRemain(src, dstMap reflect.Value, srcTypeInfo *cache.TypeInfo, include func(name string) bool) error {
+	if srcTypeInfo.RemainField == nil {
+		return nil
+	}
+
+	remain := src.Field(srcTypeInfo.RemainField.Index)
+	dstKeyType := dstMap.Type().Key()
+	dstElemType := dstMap.Type().Elem()
+
+	for _, key := range remain.MapKeys() {
+		if include != nil && !include(key.String()) {
+			continue
+		}
+
+		srcSegment := pathSegment{name: srcTypeInfo.RemainField.Name, key: key}
+		dstValue, err := s.encodeElement(keySegment(key), srcSegment, remain.MapIndex(key), dstElemType)
+		if err != nil {
+			return err
+		}
+		dstMap.SetMapIndex(key.Convert(dstKeyType), dstValue)
+	}
+
+	return nil
+}
@@ -0,0 +1,280 @@
+package mapper
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// mapSliceToMap handles mapping from a slice or array to a map
+// Each element is mapped to a map value whose key is chosen by the configured
+// key selector (KeyFunc or KeyField). Duplicate keys follow the DuplicateKeys policy.
+func (s *state) mapSliceToMap(src, dst reflect.Value) error {
+	if s.opts.KeyFunc == nil && s.opts.KeyField == "" {
+		return fmt.Errorf("%w: no key selector configured to map %s to %s", ErrMissingKey, src.Type(), dst.Type())
+	}
+
+	dstType := dst.Type()
+	dstElemType := dstType.Elem()
+
+	// Create new target Map
+	srcLen := src.Len()
+	dstMap := reflect.MakeMapWithSize(dstType, srcLen)
+
+	for i := 0; i < srcLen; i++ {
+		if err := s.checkContext(i); err != nil {
+			return err
+		}
+		srcElem := src.Index(i)
+
+		dstKey, err := s.entryKey(srcElem, i, dstMap)
+		if err != nil {
+			return err
+		}
+		if !dstKey.IsValid() {
+			continue // Duplicate key kept from an earlier element
+		}
+
+		dstValue := reflect.New(dstElemType).Elem()
+		if err := s.mapAt(nil, keySegment(dstKey), indexSegment(i), srcElem, dstValue); err != nil {
+			return err
+		}
+
+		dstMap.SetMapIndex(dstKey, dstValue)
+	}
+
+	// Set new Map to target value
+	dst.Set(dstMap)
+	return nil
+}
+
+// entryKey selects the target map key of the slice element at index i and applies the
+// duplicate key policy, reporting failures at the element's index. Returns an invalid
+// key when the element is skipped in favor of an earlier one.
+func (s *state) entryKey(srcElem reflect.Value, i int, dstMap reflect.Value) (dstKey reflect.Value, err error) {
+	s.pushIndex(i)
+	defer s.popPath()
+	defer func() {
+		if err != nil {
+			err = s.mappingError(err, srcElem.Type(), dstMap.Type().Elem())
+		}
+	}()
+
+	key, err := s.selectKey(srcElem)
+	if err != nil {
+		return reflect.Value{}, fmt.Errorf("failed to select key: %w", err)
+	}
+
+	dstKey = reflect.New(dstMap.Type().Key()).Elem()
+	if err := s.mapValue(key, dstKey); err != nil {
+		return reflect.Value{}, err
+	}
+
+	// Apply the duplicate key policy
+	if dstMap.MapIndex(dstKey).IsValid() {
+		switch s.opts.DuplicateKeys {
+		case DuplicateKeyKeepFirst:
+			return reflect.Value{}, nil
+		case DuplicateKeyKeepLast:
+		default:
+			return reflect.Value{}, fmt.Errorf("%w %v", ErrDuplicateKey, dstKey)
+		}
+	}
+	return dstKey, nil
+}
+
+// selectKey returns the map key of a slice element
+func (s *state) selectKey(elem reflect.Value) (reflect.Value, error) {
+	if s.opts.KeyFunc != nil {
+		key, err := s.opts.KeyFunc(elem.Interface())
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		if key == nil {
+			return reflect.Value{}, fmt.Errorf("%w: key selector returned nil", ErrMissingKey)
+		}
+		return reflect.ValueOf(key), nil
+	}
+
+	return resolvePath(elem, s.opts.KeyField)
+}
+
+// resolvePath follows a dotted member path through structs and maps with string keys
+func resolvePath(v reflect.Value, path string) (reflect.Value, error) {
+	for _, name := range strings.Split(path, ".") {
+		for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+			if v.IsNil() {
+				return reflect.Value{}, fmt.Errorf("%w: nil value on path %q", ErrNilSource, path)
+			}
+			v = v.Elem()
+		}
+
+		var next reflect.Value
+		switch {
+		case v.Kind() == reflect.Struct:
+			next = v.FieldByName(name)
+		case v.Kind() == reflect.Map && v.Type().Key().Kind() == reflect.String:
+			next = v.MapIndex(reflect.ValueOf(name).Convert(v.Type().Key()))
+		}
+		if !next.IsValid() {
+			return reflect.Value{}, fmt.Errorf("%w %q in %s", ErrUnmappedMember, name, v.Type())
+		}
+		v = next
+	}
+
+	return v, nil
+}
+
+// mapMapToSlice handles mapping from a map to a slice or array
+// Features:
+// 1. Entries are ordered by key, using KeyLess or the keys' natural order
+// 2. Target elements with exactly a Key and a Value field receive key/value pairs
+// 3. Other target elements, and elements the map values hold both members of, receive the map values
+func (s *state) mapMapToSlice(src, dst reflect.Value) error {
+	keys := src.MapKeys()
+	if err := s.sortKeys(keys); err != nil {
+		return err
+	}
+
+	dstType := dst.Type()
+	keyField, valueField, pairs := keyValuePairs(src.Type().Elem(), dstType.Elem())
+
+	var dstVal reflect.Value
+	mapLen := len(keys)
+	if dst.Kind() == reflect.Slice {
+		dstVal = reflect.MakeSlice(dstType, mapLen, mapLen)
+	} else {
+		dstVal = reflect.New(dstType).Elem()
+		if dstType.Len() < mapLen {
+			mapLen = dstType.Len()
+		}
+	}
+
+	for i := 0; i < mapLen; i++ {
+		if err := s.checkContext(i); err != nil {
+			return err
+		}
+		if err := s.mapMapEntry(i, src, keys[i], dstVal.Index(i), keyField, valueField, pairs); err != nil {
+			return err
+		}
+	}
+
+	dst.Set(dstVal)
+	return nil
+}
+
+// mapMapEntry maps a map entry to the slice element at index i, either the value alone or a key/value pair
+func (s *state) mapMapEntry(i int, src, key, dstElem reflect.Value, keyField, valueField int, pairs bool) error {
+	s.pushPath(indexSegment(i), keySegment(key))
+	defer s.popPath()
+
+	if !pairs {
+		return s.mapValue(src.MapIndex(key), dstElem)
+	}
+
+	if err := s.mapValue(key, dstElem.Field(keyField)); err != nil {
+		return err
+	}
+	return s.mapValue(src.MapIndex(key), dstElem.Field(valueField))
+}
+
+// keyValuePairs reports whether map entries are mapped to elements of the given type as
+// key/value pairs, which is not the case when the map values can fill the elements themselves
+func keyValuePairs(valueType, elemType reflect.Type) (keyField, valueField int, ok bool) {
+	keyField, valueField, ok = keyValueFields(elemType)
+	if !ok {
+		return 0, 0, false
+	}
+
+	for valueType.Kind() == reflect.Ptr {
+		valueType = valueType.Elem()
+	}
+	if valueType.Kind() == reflect.Struct {
+		_, hasKey := valueType.FieldByName("Key")
+		_, hasValue := valueType.FieldByName("Value")
+		if hasKey && hasValue {
+			return 0, 0, false
+		}
+	}
+
+	return keyField, valueField, true
+}
+
+// keyValueFields reports whether a struct type is a key/value pair, that is it has
+// exactly two exported fields named Key and Value, and returns their indexes
+func keyValueFields(t reflect.Type) (keyField, valueField int, ok bool) {
+	if t.Kind() != reflect.Struct || t.NumField() != 2 {
+		return 0, 0, false
+	}
+
+	key, hasKey := t.FieldByName("Key")
+	value, hasValue := t.FieldByName("Value")
+	if !hasKey || !hasValue || key.PkgPath != "" || value.PkgPath != "" {
+		return 0, 0, false
+	}
+
+	return key.Index[0], value.Index[0], true
+}
+
+// sortKeys sorts map keys using KeyLess or their natural order.
+// Keys held in interfaces are ordered by their dynamic values, which must share an ordered kind.
+// Without KeyLess, keys of other kinds have no deterministic order and are rejected.
+func (s *state) sortKeys(keys []reflect.Value) error {
+	if s.opts.KeyLess != nil {
+		sort.SliceStable(keys, func(i, j int) bool {
+			return s.opts.KeyLess(keys[i].Interface(), keys[j].Interface())
+		})
+		return nil
+	}
+
+	kind := reflect.Invalid
+	for i, key := range keys {
+		if key.Kind() == reflect.Interface {
+			key = key.Elem()
+		}
+		if !orderedKind(key.Kind()) || (i > 0 && key.Kind() != kind) {
+			return fmt.Errorf("%w: map keys of type %s have no natural order, use SortKeysBy",
+				ErrUnconvertible, keys[i].Type())
+		}
+		kind = key.Kind()
+	}
+
+	sort.SliceStable(keys, func(i, j int) bool {
+		return lessValue(keys[i], keys[j])
+	})
+	return nil
+}
+
+// orderedKind reports whether values of a kind have a natural order
+func orderedKind(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64, reflect.String, reflect.Bool:
+		return true
+	default:
+		return false
+	}
+}
+
+// lessValue orders numbers, strings and booleans of the same kind naturally,
+// unwrapping values held in interfaces
+func lessValue(a, b reflect.Value) bool {
+	if a.Kind() == reflect.Interface {
+		a, b = a.Elem(), b.Elem()
+	}
+
+	switch a.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return a.Int() < b.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return a.Uint() < b.Uint()
+	case reflect.Float32, reflect.Float64:
+		return a.Float() < b.Float()
+	case reflect.String:
+		return a.String() < b.String()
+	default:
+		return !a.Bool() && b.Bool()
+	}
+}
@@ -0,0 +1,233 @@
+package mapper
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// appendCollection appends the mapped source elements to the existing target slice
+func (s *state) appendCollection(src, dst reflect.Value) error {
+	srcLen := src.Len()
+	dstLen := dst.Len()
+
+	// Build a new slice so the existing backing array is not modified
+	dstVal := reflect.MakeSlice(dst.Type(), dstLen+srcLen, dstLen+srcLen)
+	reflect.Copy(dstVal, dst)
+
+	for i := 0; i < srcLen; i++ {
+		if err := s.checkContext(i); err != nil {
+			return err
+		}
+		if err := s.mapAt(nil, indexSegment(dstLen+i), indexSegment(i), src.Index(i), dstVal.Index(dstLen+i)); err != nil {
+			return err
+		}
+	}
+
+	dst.Set(dstVal)
+	return nil
+}
+
+// mergeCollectionByIndex maps each source element onto the existing target element at the same index.
+// Slices take the source length: extra source elements are added and extra target elements dropped.
+// Arrays keep their length and only the overlapping elements are merged.
+func (s *state) mergeCollectionByIndex(src, dst reflect.Value) error {
+	srcLen := src.Len()
+	dstVal := dst
+	mapLen := srcLen
+
+	if dst.Kind() == reflect.Slice {
+		if srcLen > dst.Len() {
+			dstVal = reflect.MakeSlice(dst.Type(), srcLen, srcLen)
+			reflect.Copy(dstVal, dst)
+		} else {
+			dstVal = dst.Slice(0, srcLen)
+		}
+	} else if dst.Len() < mapLen {
+		mapLen = dst.Len()
+	}
+
+	for i := 0; i < mapLen; i++ {
+		if err := s.checkContext(i); err != nil {
+			return err
+		}
+		if err := s.mergeAt(indexSegment(i), indexSegment(i), src.Index(i), dstVal.Index(i)); err != nil {
+			return err
+		}
+	}
+
+	if dst.Kind() == reflect.Slice {
+		dst.Set(dstVal)
+	}
+	return nil
+}
+
+// syncCollectionByKey matches source and target elements by the SyncKey identity member.
+// Matching target elements are updated in place, unmatched source elements are appended,
+// and unmatched target elements are kept unless SyncRemoveMissing is set.
+func (s *state) syncCollectionByKey(src, dst reflect.Value) error {
+	if s.opts.SyncKey == "" {
+		return fmt.Errorf("%w: no sync key configured to map %s to %s", ErrMissingKey, src.Type(), dst.Type())
+	}
+
+	// Index existing target elements by key
+	dstLen := dst.Len()
+	existing := make(map[any]int, dstLen)
+	var keyType reflect.Type
+	for i := 0; i < dstLen; i++ {
+		key, err := s.elementSyncKey(dst.Index(i), i, nil, dst.Type().Elem(), "target element")
+		if err != nil {
+			return err
+		}
+		keyType = key.Type()
+		existing[key.Interface()] = i
+	}
+
+	// Update matches and map new elements
+	srcLen := src.Len()
+	matched := make([]bool, dstLen)
+	var added []reflect.Value
+	for i := 0; i < srcLen; i++ {
+		if err := s.checkContext(i); err != nil {
+			return err
+		}
+		srcElem := src.Index(i)
+
+		key, err := s.elementSyncKey(srcElem, i, keyType, dst.Type().Elem(), "element")
+		if err != nil {
+			return err
+		}
+
+		if j, found := existing[key.Interface()]; found {
+			matched[j] = true
+			if err := s.mergeAt(indexSegment(j), indexSegment(i), srcElem, dst.Index(j)); err != nil {
+				return err
+			}
+			continue
+		}
+
+		dstElem := reflect.New(dst.Type().Elem()).Elem()
+		if err := s.mapAt(nil, indexSegment(dstLen+len(added)), indexSegment(i), srcElem, dstElem); err != nil {
+			return err
+		}
+		added = append(added, dstElem)
+	}
+
+	// Build the result: existing elements in order, then new ones
+	dstVal := reflect.MakeSlice(dst.Type(), 0, dstLen+len(added))
+	for j := 0; j < dstLen; j++ {
+		if matched[j] || !s.opts.SyncRemoveMissing {
+			dstVal = reflect.Append(dstVal, dst.Index(j))
+		}
+	}
+	dstVal = reflect.Append(dstVal, added...)
+
+	dst.Set(dstVal)
+	return nil
+}
+
+// elementSyncKey reads the identity member of the element at index i of a source or
+// target collection, reporting failures at the element's index
+func (s *state) elementSyncKey(elem reflect.Value, i int, keyType, dstType reflect.Type, role string) (reflect.Value, error) {
+	s.pushIndex(i)
+	defer s.popPath()
+
+	key, err := s.syncKey(elem, keyType)
+	if err != nil {
+		return reflect.Value{}, s.mappingError(fmt.Errorf("failed to read sync key of %s: %w", role, err), elem.Type(), dstType)
+	}
+	return key, nil
+}
+
+// syncKey reads the identity member of an element, converting it to keyType when known
+func (s *state) syncKey(elem reflect.Value, keyType reflect.Type) (reflect.Value, error) {
+	key, err := resolvePath(elem, s.opts.SyncKey)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+	if !key.Type().Comparable() {
+		return reflect.Value{}, fmt.Errorf("%w sync key %q of type %s to a comparable key", ErrUnconvertible, s.opts.SyncKey, key.Type())
+	}
+
+	if keyType != nil && key.Type() != keyType {
+		if !key.Type().ConvertibleTo(keyType) {
+			return reflect.Value{}, fmt.Errorf("%w sync key from %s to %s", ErrUnconvertible, key.Type(), keyType)
+		}
+		key = key.Convert(keyType)
+	}
+	return key, nil
+}
+
+// mergeValue maps a source element onto an existing target element,
+// keeping the identity of target pointers instead of replacing them
+func (s *state) mergeValue(src, dst reflect.Value) error {
+	if src.Kind() == reflect.Ptr && dst.Kind() == reflect.Ptr && !src.IsNil() && !dst.IsNil() {
+		return s.mapValue(src.Elem(), dst.Elem())
+	}
+	return s.mapValue(src, dst)
+}
+
+// mergeAt merges src onto dst within a target member and the source member mapped to it,
+// leaving the members even when merging panics
+func (s *state) mergeAt(dstSegment, srcSegment pathSegment, src, dst reflect.Value) error {
+	s.pushPath(dstSegment, srcSegment)
+	defer s.popPath()
+	return s.mergeValue(src, dst)
+}
+
+// mergeMap updates the existing target map in place with the entries of the source map.
+// Target keys missing from the source are kept unless MapRemoveMissing is set.
+// MapMerge overwrites the entries present in the source, MapDeepMerge maps them onto the existing entries,
+// as does a field mask selecting members of the values.
+func (s *state) mergeMap(src, dst reflect.Value) error {
+	dstType := dst.Type()
+	dstElemType := dstType.Elem()
+
+	var seen map[interface{}]bool
+	if s.opts.MapRemoveMissing {
+		seen = make(map[interface{}]bool, src.Len())
+	}
+
+	iter := src.MapRange()
+	for i := 0; iter.Next(); i++ {
+		if err := s.checkContext(i); err != nil {
+			return err
+		}
+		dstKey, err := s.mapKey(iter.Key(), dstType.Key())
+		if err != nil {
+			return err
+		}
+		if seen != nil {
+			seen[dstKey.Interface()] = true
+		}
+
+		// Skipped entries keep the existing target entry
+		if s.skips(iter.Value()) {
+			continue
+		}
+
+		dstValue := reflect.New(dstElemType).Elem()
+		dstSegment, srcSegment := keySegment(dstKey), keySegment(iter.Key())
+		if existing := dst.MapIndex(dstKey); (s.opts.Maps == MapDeepMerge || s.mask != nil) && existing.IsValid() {
+			// Map values are not addressable, so merge into a copy and store it back
+			dstValue.Set(existing)
+			err = s.mergeAt(dstSegment, srcSegment, iter.Value(), dstValue)
+		} else {
+			err = s.mapAt(nil, dstSegment, srcSegment, iter.Value(), dstValue)
+		}
+		if err != nil {
+			return err
+		}
+
+		dst.SetMapIndex(dstKey, dstValue)
+	}
+
+	if seen != nil {
+		for _, key := range dst.MapKeys() {
+			if !seen[key.Interface()] {
+				dst.SetMapIndex(key, reflect.Value{})
+			}
+		}
+	}
+
+	return nil
+}
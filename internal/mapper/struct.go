@@ -10,64 +10,175 @@ import (
 
 // mapStruct handles mapping from struct to struct
 // This function iterates through all fields of the target struct and attempts to find corresponding fields in the source struct
-func mapStruct(src, dst reflect.Value) error {
+func (s *state) mapStruct(src, dst reflect.Value) error {
 	// Verify source value is a struct
 	if src.Kind() != reflect.Struct {
-		return fmt.Errorf("source value is not a struct, but %s", src.Kind())
+		return fmt.Errorf("%w: source value is not a struct, but %s", ErrUnconvertible, src.Kind())
 	}
 
 	// Verify target value is a struct
 	if dst.Kind() != reflect.Struct {
-		return fmt.Errorf("target value is not a struct, but %s", dst.Kind())
+		return fmt.Errorf("%w: target value is not a struct, but %s", ErrUnconvertible, dst.Kind())
 	}
 
-	// Get source and target type information from cache
-	typeCache := cache.GetGlobalCache()
-	srcType := src.Type()
-	dstType := dst.Type()
+	return s.mapCompiledStruct(compileStruct(src.Type(), dst.Type()), src, dst)
+}
+
+// structPlan is the compiled mapping of a struct pair: the type information of both structs,
+// the registered member resolvers, the fields copied as memory and the source field read by
+// each target field of the same name
+type structPlan struct {
+	src     *cache.TypeInfo
+	dst     *cache.TypeInfo
+	members map[string]MemberFunc
+	layout  *structLayout
+	sources []int // source field index by target field index, -1 when the member is looked up
+}
+
+// compileStruct returns the plan of a struct pair, compiling it on first use.
+// Plans are cached with the registered pairs, which provide the member resolvers
+// and decide the fields copied as memory.
+func compileStruct(srcType, dstType reflect.Type) *structPlan {
+	structs := &GetGlobalRegistry().load().structs
+	key := PairKey{Src: srcType, Dst: dstType}
+	if cached, ok := structs.Load(key); ok {
+		return cached.(*structPlan)
+	}
 
 	// 使用 GetOrCreate 方法获取或创建类型信息
-	srcTypeInfo := typeCache.GetOrCreate(srcType)
-	dstTypeInfo := typeCache.GetOrCreate(dstType)
+	typeCache := cache.GetGlobalCache()
+	plan := &structPlan{
+		src:     typeCache.GetOrCreate(srcType),
+		dst:     typeCache.GetOrCreate(dstType),
+		members: memberResolvers(srcType, dstType),
+		layout:  buildLayout(srcType, dstType),
+		sources: make([]int, dstType.NumField()),
+	}
+
+	// Target fields without a source field of the same name look up embedded, nested and remain members
+	for i := range plan.sources {
+		plan.sources[i] = -1
+	}
+	for _, fieldInfo := range plan.dst.Fields {
+		if srcFieldInfo, exists := plan.src.FieldsMap[fieldInfo.Name]; exists {
+			plan.sources[fieldInfo.Index] = srcFieldInfo.Index
+		}
+	}
+
+	structs.Store(key, plan)
+	return plan
+}
+
+// mapCompiledStruct maps a source struct onto a target struct with the plan of their pair
+func (s *state) mapCompiledStruct(plan *structPlan, src, dst reflect.Value) error {
+	// Structs with invalid field tags are not mapped
+	if plan.src.TagErr != nil {
+		return plan.src.TagErr
+	}
+	if plan.dst.TagErr != nil {
+		return plan.dst.TagErr
+	}
+
+	// Copy plain fields of identical types as memory, then map the remaining fields
+	fields := plan.dst.Fields
+	if s.copiesLayout(plan.members) {
+		fields = copyLayout(src, dst, plan.layout, plan.dst)
+	}
 
 	// Use cached field information for target struct
-	for _, fieldInfo := range dstTypeInfo.Fields {
-		// Get target field
-		dstField := dst.Field(fieldInfo.Index)
-
-		// Field is already verified as exported in BuildTypeInfo
-		// Get field name
-		fieldName := fieldInfo.Name
-
-		// Find corresponding field in source struct using cached field map
-		srcFieldInfo, exists := srcTypeInfo.FieldsMap[fieldName]
-		if exists {
-			srcField := src.Field(srcFieldInfo.Index)
-			// Recursively map field value
-			if err := MapValue(srcField, dstField); err != nil {
-				return fmt.Errorf("failed to map field %s: %w", fieldName, err)
-			}
-		} else {
-			// Try to find in embedded fields
-			if embeddedField, found := findFieldInEmbedded(src, fieldName); found {
-				if err := MapValue(embeddedField, dstField); err != nil {
-					return fmt.Errorf("failed to map field %s from embedded: %w", fieldName, err)
-				}
-			} else {
-				// Try to find in nested fields with flattening
-				if nestedField, found := findNestedField(src, fieldName); found {
-					if err := MapValue(nestedField, dstField); err != nil {
-						return fmt.Errorf("failed to map nested field %s: %w", fieldName, err)
-					}
-				}
-				// If field not found, skip (keep original value in target field)
-			}
+	for _, fieldInfo := range fields {
+		if err := s.mapStructField(src, dst, plan, fieldInfo); err != nil {
+			return err
 		}
 	}
 
+	// Collect unmatched source members into the target's remain field
+	if plan.dst.RemainField != nil {
+		return s.mapRemainingFields(src, dst, plan.src, plan.dst)
+	}
+
 	return nil
 }
 
+// mapStructField maps a single target field from the corresponding source member
+// Members with a registered resolver take their value from it instead.
+func (s *state) mapStructField(src, dst reflect.Value, plan *structPlan, fieldInfo cache.FieldInfo) error {
+	// Apply the options overridden by the field's tag
+	if fieldInfo.Tag.OverridesOptions() {
+		saved := s.applyFieldTag(fieldInfo.Tag)
+		defer func() { s.opts = saved }()
+	}
+
+	// Skip fields outside the field mask
+	savedMask, selected := s.selectField(fieldInfo.Name)
+	if !selected {
+		return nil
+	}
+	defer func() { s.mask = savedMask }()
+
+	// Get target field
+	dstField := dst.Field(fieldInfo.Index)
+
+	// Field is already verified as exported in BuildTypeInfo
+	// Get field name
+	fieldName := fieldInfo.Name
+
+	if resolve, ok := plan.members[fieldName]; ok {
+		return s.resolveMember(resolve, src, dstField, fieldName)
+	}
+
+	srcField, srcSegment, found := plan.sourceMember(src, fieldInfo)
+	if !found {
+		// If field not found, skip (keep original value in target field)
+		return nil
+	}
+
+	// Recursively map field value
+	s.pushPath(fieldSegment(fieldName), srcSegment)
+	defer s.popPath()
+	if err := s.mapValue(srcField, dstField); err != nil {
+		return s.collectError(err)
+	}
+	return nil
+}
+
+// sourceMember returns the source member of a target field, reading the source field of the
+// same name directly and looking up other members with findSourceMember
+func (plan *structPlan) sourceMember(src reflect.Value, fieldInfo cache.FieldInfo) (reflect.Value, pathSegment, bool) {
+	if i := plan.sources[fieldInfo.Index]; i >= 0 {
+		return src.Field(i), fieldSegment(fieldInfo.Name), true
+	}
+	return findSourceMember(src, plan.src, fieldInfo.Name)
+}
+
+// findSourceMember finds the source member for a target field: a field with the same name,
+// a field promoted from an embedded struct, a flattened nested field or an entry of the
+// source's remain field. Also returns the source path segment of the member.
+func findSourceMember(src reflect.Value, srcTypeInfo *cache.TypeInfo, fieldName string) (reflect.Value, pathSegment, bool) {
+	// Find corresponding field in source struct using cached field map
+	if srcFieldInfo, exists := srcTypeInfo.FieldsMap[fieldName]; exists {
+		return src.Field(srcFieldInfo.Index), fieldSegment(fieldName), true
+	}
+
+	// Try to find in embedded fields
+	if embeddedField, found := findFieldInEmbedded(src, fieldName); found {
+		return embeddedField, fieldSegment(fieldName), true
+	}
+
+	// Try to find in nested fields with flattening
+	if nestedField, found := findNestedField(src, fieldName); found {
+		return nestedField, fieldSegment(fieldName), true
+	}
+
+	// Try to find in the source's remain field
+	if remainValue, found := findRemainValue(src, srcTypeInfo, fieldName); found {
+		segment := pathSegment{name: srcTypeInfo.RemainField.Name, key: reflect.ValueOf(fieldName)}
+		return remainValue, segment, true
+	}
+
+	return reflect.Value{}, pathSegment{}, false
+}
+
 // findSourceField finds a field with the specified name in the source struct
 // Only performs exact name matching
 func findSourceField(src reflect.Value, fieldName string) reflect.Value {
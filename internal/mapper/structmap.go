@@ -0,0 +1,392 @@
+package mapper
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+
+	"github.com/deferz/go-mapster/internal/cache"
+)
+
+// Commonly used reflect types for struct <-> map conversion
+var (
+	anyType      = reflect.TypeOf((*any)(nil)).Elem()
+	anyMapType   = reflect.TypeOf(map[string]any(nil))
+	anySliceType = reflect.TypeOf([]any(nil))
+)
+
+// mapStructToMap handles mapping from a struct to a map with string keys
+// Features:
+// 1. Each exported field is stored under its field name
+// 2. Fields of embedded structs are promoted into the same map
+// 3. For interface values (e.g. map[string]any), nested structs become nested maps
+func (s *state) mapStructToMap(src, dst reflect.Value) error {
+	// Verify source value is a struct
+	if src.Kind() != reflect.Struct {
+		return fmt.Errorf("%w: source value is not a struct, but %s", ErrUnconvertible, src.Kind())
+	}
+
+	// Verify target value is a map with string keys
+	if dst.Kind() != reflect.Map || dst.Type().Key().Kind() != reflect.String {
+		return fmt.Errorf("%w: target value is not a map with string keys, but %s", ErrUnconvertible, dst.Type())
+	}
+
+	// Create new target Map
+	dstMap := reflect.MakeMap(dst.Type())
+	if err := s.writeStructFields(src, dstMap, nil); err != nil {
+		return err
+	}
+
+	// Set new Map to target value
+	dst.Set(dstMap)
+	return nil
+}
+
+// writeStructFields stores the exported fields of a struct into a map with string keys.
+// When include is not nil, only the members it accepts are encoded and stored.
+func (s *state) writeStructFields(src, dstMap reflect.Value, include func(name string) bool) error {
+	typeInfo := cache.GetGlobalCache().GetOrCreate(src.Type())
+	if typeInfo.TagErr != nil {
+		return typeInfo.TagErr
+	}
+	dstKeyType := dstMap.Type().Key()
+	dstElemType := dstMap.Type().Elem()
+
+	// Spread the remain field first so that real fields take precedence
+	if err := s.spreadRemain(src, dstMap, typeInfo, include); err != nil {
+		return err
+	}
+
+	// Promote embedded fields first so that direct fields take precedence
+	for _, fieldInfo := range typeInfo.Fields {
+		if !fieldInfo.IsAnonymous {
+			continue
+		}
+		embedded, ok := embeddedStruct(src.Field(fieldInfo.Index))
+		if !ok {
+			continue
+		}
+		if err := s.writeStructFields(embedded, dstMap, include); err != nil {
+			return err
+		}
+	}
+
+	for _, fieldInfo := range typeInfo.Fields {
+		srcField := src.Field(fieldInfo.Index)
+		if fieldInfo.IsAnonymous {
+			if _, ok := embeddedStruct(srcField); ok {
+				continue
+			}
+		}
+		if include != nil && !include(fieldInfo.Name) {
+			continue
+		}
+
+		dstKey := reflect.ValueOf(fieldInfo.Name).Convert(dstKeyType)
+		dstValue, err := s.encodeElement(keySegment(dstKey), fieldSegment(fieldInfo.Name), srcField, dstElemType)
+		if err != nil {
+			return err
+		}
+
+		dstMap.SetMapIndex(dstKey, dstValue)
+	}
+
+	return nil
+}
+
+// mapMapToStruct handles mapping from a map with string keys to a struct
+// Keys are matched against target field names, and embedded struct fields
+// are filled from the same map. Fields without a matching key keep their value.
+// Keys matching no field are collected into the target's remain field, if any.
+func (s *state) mapMapToStruct(src, dst reflect.Value) error {
+	// Verify source value is a map with string keys
+	if src.Kind() != reflect.Map || src.Type().Key().Kind() != reflect.String {
+		return fmt.Errorf("%w: source value is not a map with string keys, but %s", ErrUnconvertible, src.Type())
+	}
+
+	// Verify target value is a struct
+	if dst.Kind() != reflect.Struct {
+		return fmt.Errorf("%w: target value is not a struct, but %s", ErrUnconvertible, dst.Kind())
+	}
+
+	dstTypeInfo := cache.GetGlobalCache().GetOrCreate(dst.Type())
+	if dstTypeInfo.TagErr != nil {
+		return dstTypeInfo.TagErr
+	}
+	if dstTypeInfo.RemainField == nil {
+		return s.decodeStruct(src, dst, nil)
+	}
+
+	consumed := make(map[string]bool)
+	if err := s.decodeStruct(src, dst, consumed); err != nil {
+		return err
+	}
+	return s.mapRemainingKeys(src, dst, dstTypeInfo, consumed)
+}
+
+// decodeStruct fills the target struct fields from the map entries with matching keys.
+// Matched keys are recorded in consumed when it is not nil.
+func (s *state) decodeStruct(src, dst reflect.Value, consumed map[string]bool) error {
+	dstTypeInfo := cache.GetGlobalCache().GetOrCreate(dst.Type())
+	srcKeyType := src.Type().Key()
+
+	for _, fieldInfo := range dstTypeInfo.Fields {
+		if err := s.decodeStructField(src, dst, srcKeyType, fieldInfo, consumed); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// decodeStructField fills a single target field from the map entry with a matching key
+func (s *state) decodeStructField(src, dst reflect.Value, srcKeyType reflect.Type, fieldInfo cache.FieldInfo, consumed map[string]bool) error {
+	// Apply the options overridden by the field's tag
+	if fieldInfo.Tag.OverridesOptions() {
+		saved := s.applyFieldTag(fieldInfo.Tag)
+		defer func() { s.opts = saved }()
+	}
+
+	// Skip fields outside the field mask
+	savedMask, selected := s.selectField(fieldInfo.Name)
+	if !selected {
+		return nil
+	}
+	defer func() { s.mask = savedMask }()
+
+	dstField := dst.Field(fieldInfo.Index)
+
+	srcKey := reflect.ValueOf(fieldInfo.Name).Convert(srcKeyType)
+	srcValue := src.MapIndex(srcKey)
+	if srcValue.IsValid() {
+		if consumed != nil {
+			consumed[fieldInfo.Name] = true
+		}
+		s.pushPath(fieldSegment(fieldInfo.Name), keySegment(srcKey))
+		defer s.popPath()
+		if err := s.mapValue(srcValue, dstField); err != nil {
+			return s.collectError(err)
+		}
+		return nil
+	}
+
+	// Fill embedded structs from the same map
+	if fieldInfo.IsAnonymous {
+		return s.mapMapToEmbedded(src, dstField, consumed)
+	}
+
+	return nil
+}
+
+// mapMapToEmbedded fills an embedded struct field from the keys of the parent map
+func (s *state) mapMapToEmbedded(src, dstField reflect.Value, consumed map[string]bool) error {
+	structType := dstField.Type()
+	if structType.Kind() == reflect.Ptr {
+		structType = structType.Elem()
+	}
+	if structType.Kind() != reflect.Struct {
+		return nil
+	}
+
+	if dstField.Kind() == reflect.Ptr {
+		// Only allocate embedded pointers when the map provides one of their fields
+		if dstField.IsNil() {
+			if !mapHasStructKeys(src, structType) {
+				return nil
+			}
+			dstField.Set(reflect.New(structType))
+		}
+		dstField = dstField.Elem()
+	}
+
+	return s.decodeStruct(src, dstField, consumed)
+}
+
+// mapHasStructKeys reports whether the map contains a key for any field of the struct type
+func mapHasStructKeys(src reflect.Value, structType reflect.Type) bool {
+	typeInfo := cache.GetGlobalCache().GetOrCreate(structType)
+	srcKeyType := src.Type().Key()
+
+	for _, fieldInfo := range typeInfo.Fields {
+		if src.MapIndex(reflect.ValueOf(fieldInfo.Name).Convert(srcKeyType)).IsValid() {
+			return true
+		}
+		if fieldInfo.IsAnonymous {
+			fieldType := fieldInfo.Type
+			if fieldType.Kind() == reflect.Ptr {
+				fieldType = fieldType.Elem()
+			}
+			if fieldType.Kind() == reflect.Struct && mapHasStructKeys(src, fieldType) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// embeddedStruct returns the struct held by an embedded field, dereferencing pointers
+func embeddedStruct(field reflect.Value) (reflect.Value, bool) {
+	if field.Kind() == reflect.Ptr {
+		if field.IsNil() {
+			return reflect.Value{}, false
+		}
+		field = field.Elem()
+	}
+	return field, field.Kind() == reflect.Struct
+}
+
+// encodeElement converts a struct field into a value of the target map's element type,
+// within the given target entry and source member
+func (s *state) encodeElement(dstSegment, srcSegment pathSegment, src reflect.Value, elemType reflect.Type) (reflect.Value, error) {
+	s.pushPath(dstSegment, srcSegment)
+	defer s.popPath()
+
+	dstValue := reflect.New(elemType).Elem()
+
+	switch {
+	case elemType == anyType:
+		// Untyped elements receive plain maps and slices instead of structs
+		encoded, err := s.encodeValue(src)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		if encoded.IsValid() {
+			dstValue.Set(encoded)
+		}
+	case elemType.Kind() == reflect.String:
+		for src.Kind() == reflect.Ptr || src.Kind() == reflect.Interface {
+			if src.IsNil() {
+				return dstValue, nil
+			}
+			src = src.Elem()
+		}
+		if formatted, ok := formatScalar(src); ok {
+			dstValue.SetString(formatted)
+		} else if err := s.mapValue(src, dstValue); err != nil {
+			return reflect.Value{}, err
+		}
+	default:
+		if err := s.mapValue(src, dstValue); err != nil {
+			return reflect.Value{}, err
+		}
+	}
+
+	return dstValue, nil
+}
+
+// encodeAt encodes a member of a value within its path segment, leaving the segment even when encoding panics
+func (s *state) encodeAt(segment pathSegment, v reflect.Value) (reflect.Value, error) {
+	s.pushPath(segment, segment)
+	defer s.popPath()
+	return s.encodeValue(v)
+}
+
+// encodeValue converts a value into its generic representation:
+// structs become map[string]any and collections containing structs become []any or map[string]any.
+// Returns an invalid value for nil pointers and interfaces, and for references
+// closing a cycle unless the cycle policy reports them as errors.
+func (s *state) encodeValue(v reflect.Value) (reflect.Value, error) {
+	// Enforce the configured limits on nested values
+	if s.limitsNesting(v) {
+		if err := s.enterLevel(v, anyType); err != nil {
+			return reflect.Value{}, err
+		}
+		defer s.leaveLevel()
+	}
+
+	switch v.Kind() {
+	case reflect.Interface:
+		if v.IsNil() {
+			return reflect.Value{}, nil
+		}
+		return s.encodeValue(v.Elem())
+	case reflect.Ptr:
+		if v.IsNil() {
+			return reflect.Value{}, nil
+		}
+		if key, tracked := trackRef(v); tracked {
+			if depth, cyclic := s.visiting[key]; cyclic {
+				return reflect.Value{}, s.handleCycle(reflect.New(anyType).Elem(), key, depth)
+			}
+			s.enterRef(key)
+			defer s.leaveRef(key)
+		}
+		return s.encodeValue(v.Elem())
+	case reflect.Struct:
+		// Opaque structs such as time.Time are kept as they are
+		if len(cache.GetGlobalCache().GetOrCreate(v.Type()).Fields) == 0 {
+			return v, nil
+		}
+		m := reflect.MakeMap(anyMapType)
+		if err := s.writeStructFields(v, m, nil); err != nil {
+			return reflect.Value{}, err
+		}
+		return m, nil
+	case reflect.Slice, reflect.Array:
+		if !needsEncoding(v.Type().Elem()) || (v.Kind() == reflect.Slice && v.IsNil()) {
+			return v, nil
+		}
+		out := reflect.MakeSlice(anySliceType, v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			encoded, err := s.encodeAt(indexSegment(i), v.Index(i))
+			if err != nil {
+				return reflect.Value{}, err
+			}
+			if encoded.IsValid() {
+				out.Index(i).Set(encoded)
+			}
+		}
+		return out, nil
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String || !needsEncoding(v.Type().Elem()) || v.IsNil() {
+			return v, nil
+		}
+		out := reflect.MakeMapWithSize(anyMapType, v.Len())
+		for _, key := range v.MapKeys() {
+			encoded, err := s.encodeAt(keySegment(key), v.MapIndex(key))
+			if err != nil {
+				return reflect.Value{}, err
+			}
+			if !encoded.IsValid() {
+				encoded = reflect.Zero(anyType)
+			}
+			out.SetMapIndex(reflect.ValueOf(key.String()), encoded)
+		}
+		return out, nil
+	default:
+		return v, nil
+	}
+}
+
+// needsEncoding reports whether values of the type may hold structs that encodeValue converts
+func needsEncoding(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Interface, reflect.Ptr, reflect.Struct:
+		return true
+	case reflect.Slice, reflect.Array:
+		return needsEncoding(t.Elem())
+	case reflect.Map:
+		return t.Key().Kind() == reflect.String && needsEncoding(t.Elem())
+	default:
+		return false
+	}
+}
+
+// formatScalar formats numbers and booleans as strings
+func formatScalar(v reflect.Value) (string, bool) {
+	switch v.Kind() {
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool()), true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(v.Uint(), 10), true
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'g', -1, v.Type().Bits()), true
+	case reflect.String:
+		return v.String(), true
+	default:
+		return "", false
+	}
+}
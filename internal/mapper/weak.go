@@ -0,0 +1,193 @@
+package mapper
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// mapWeak applies the lenient conversions of weakly typed mode:
+// 1. Empty strings become nil pointers
+// 2. Comma-separated strings become string slices, other scalars single-element slices
+// 3. Strings such as "1" or "true" become booleans, numbers become non-zero checks
+// 4. Integral floats and numeric strings become integers, booleans become 1 or 0
+// 5. Numbers and booleans become their string representation
+//
+// Returns handled=false when no weak rule applies and regular mapping should continue.
+func (s *state) mapWeak(src, dst reflect.Value) (bool, error) {
+	srcKind := src.Kind()
+	dstType := dst.Type()
+
+	switch dst.Kind() {
+	case reflect.Ptr:
+		if srcKind == reflect.String && src.Len() == 0 {
+			dst.Set(reflect.Zero(dstType))
+			return true, nil
+		}
+	case reflect.Slice:
+		if srcKind == reflect.Slice || srcKind == reflect.Array || srcKind == reflect.Map ||
+			src.Type().ConvertibleTo(dstType) {
+			return false, nil
+		}
+		return true, s.mapWeakSlice(src, dst)
+	case reflect.Bool:
+		return mapWeakBool(src, dst)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return mapWeakInt(src, dst)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return mapWeakUint(src, dst)
+	case reflect.Float32, reflect.Float64:
+		return mapWeakFloat(src, dst)
+	case reflect.String:
+		if srcKind != reflect.String {
+			if formatted, ok := formatScalar(src); ok {
+				dst.SetString(formatted)
+				return true, nil
+			}
+		}
+	}
+
+	return false, nil
+}
+
+// mapWeakSlice wraps a scalar source into a slice target
+func (s *state) mapWeakSlice(src, dst reflect.Value) error {
+	dstType := dst.Type()
+
+	// Split comma-separated strings into string slices
+	if src.Kind() == reflect.String && dstType.Elem().Kind() == reflect.String {
+		var parts []string
+		if src.Len() > 0 {
+			parts = strings.Split(src.String(), ",")
+		}
+
+		dstSlice := reflect.MakeSlice(dstType, len(parts), len(parts))
+		for i, part := range parts {
+			dstSlice.Index(i).SetString(strings.TrimSpace(part))
+		}
+		dst.Set(dstSlice)
+		return nil
+	}
+
+	// Any other single value becomes a one-element slice
+	dstSlice := reflect.MakeSlice(dstType, 1, 1)
+	if err := s.mapValue(src, dstSlice.Index(0)); err != nil {
+		return err
+	}
+	dst.Set(dstSlice)
+	return nil
+}
+
+// mapWeakBool converts strings and numbers to booleans
+func mapWeakBool(src, dst reflect.Value) (bool, error) {
+	switch src.Kind() {
+	case reflect.String:
+		value, err := strconv.ParseBool(strings.TrimSpace(src.String()))
+		if err != nil {
+			return true, fmt.Errorf("%w %q to %s", ErrUnconvertible, src.String(), dst.Type())
+		}
+		dst.SetBool(value)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		dst.SetBool(src.Int() != 0)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		dst.SetBool(src.Uint() != 0)
+	case reflect.Float32, reflect.Float64:
+		dst.SetBool(src.Float() != 0)
+	default:
+		return false, nil
+	}
+	return true, nil
+}
+
+// mapWeakInt converts integral floats, numeric strings and booleans to signed integers
+func mapWeakInt(src, dst reflect.Value) (bool, error) {
+	value, handled, err := weakInteger(src, dst.Type())
+	if !handled || err != nil {
+		return handled, err
+	}
+
+	if dst.OverflowInt(value) {
+		return true, fmt.Errorf("%w: value %d overflows %s", ErrUnconvertible, value, dst.Type())
+	}
+	dst.SetInt(value)
+	return true, nil
+}
+
+// mapWeakUint converts integral floats, numeric strings and booleans to unsigned integers
+func mapWeakUint(src, dst reflect.Value) (bool, error) {
+	value, handled, err := weakInteger(src, dst.Type())
+	if !handled || err != nil {
+		return handled, err
+	}
+
+	if value < 0 || dst.OverflowUint(uint64(value)) {
+		return true, fmt.Errorf("%w: value %d overflows %s", ErrUnconvertible, value, dst.Type())
+	}
+	dst.SetUint(uint64(value))
+	return true, nil
+}
+
+// weakInteger extracts an integer from integral floats, numeric strings and booleans.
+// Returns handled=false for other source kinds.
+func weakInteger(src reflect.Value, dstType reflect.Type) (value int64, handled bool, err error) {
+	switch src.Kind() {
+	case reflect.Float32, reflect.Float64:
+		f := src.Float()
+		if f != math.Trunc(f) || f < math.MinInt64 || f >= math.MaxInt64 {
+			return 0, true, fmt.Errorf("%w non-integral %v to %s", ErrUnconvertible, f, dstType)
+		}
+		return int64(f), true, nil
+	case reflect.String:
+		parsed, err := parseIntegral(src.String())
+		if err != nil {
+			return 0, true, fmt.Errorf("%w %q to %s", ErrUnconvertible, src.String(), dstType)
+		}
+		return parsed, true, nil
+	case reflect.Bool:
+		if src.Bool() {
+			return 1, true, nil
+		}
+		return 0, true, nil
+	default:
+		return 0, false, nil
+	}
+}
+
+// mapWeakFloat converts numeric strings and booleans to floats
+func mapWeakFloat(src, dst reflect.Value) (bool, error) {
+	var value float64
+
+	switch src.Kind() {
+	case reflect.String:
+		parsed, err := strconv.ParseFloat(strings.TrimSpace(src.String()), dst.Type().Bits())
+		if err != nil {
+			return true, fmt.Errorf("%w %q to %s", ErrUnconvertible, src.String(), dst.Type())
+		}
+		value = parsed
+	case reflect.Bool:
+		if src.Bool() {
+			value = 1
+		}
+	default:
+		return false, nil
+	}
+
+	dst.SetFloat(value)
+	return true, nil
+}
+
+// parseIntegral parses an integer, also accepting integral floats such as "1.0"
+func parseIntegral(s string) (int64, error) {
+	s = strings.TrimSpace(s)
+	if value, err := strconv.ParseInt(s, 0, 64); err == nil {
+		return value, nil
+	}
+
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil || f != math.Trunc(f) || f < math.MinInt64 || f >= math.MaxInt64 {
+		return 0, fmt.Errorf("invalid integer %q", s)
+	}
+	return int64(f), nil
+}
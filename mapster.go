@@ -1,8 +1,10 @@
 package mapster
 
 import (
+	"context"
 	"fmt"
 	"reflect"
+	"unsafe"
 
 	"github.com/deferz/go-mapster/internal/cache"
 	"github.com/deferz/go-mapster/internal/mapper"
@@ -11,16 +13,23 @@ import (
 // Map maps the source object to the target type and returns a new instance.
 // This function uses generics to ensure type safety, checking type matches at compile time.
 // Mapping between source and target types is automatically registered on first use.
-func Map[T any](src any) (T, error) {
+// Options apply to this call only.
+func Map[T any](src any, opts ...Option) (T, error) {
+	return MapCtx[T](context.Background(), src, opts...)
+}
+
+// MapCtx is like Map, passing ctx to the converters, hooks and member resolvers
+// of registered pairs. Mapping stops with ctx's error once ctx is done.
+func MapCtx[T any](ctx context.Context, src any, opts ...Option) (T, error) {
 	var result T
 	if src == nil {
-		return result, fmt.Errorf("source cannot be nil")
+		return result, ErrNilSource
 	}
 
 	// Get types
 	typeCache := cache.GetGlobalCache()
 	sourceType := reflect.TypeOf(src)
-	targetType := reflect.TypeOf(result)
+	targetType := typeOf[T]()
 
 	// Auto-register the mapping if not already registered
 	if !typeCache.IsRegistered(sourceType, targetType) {
@@ -28,7 +37,7 @@ func Map[T any](src any) (T, error) {
 	}
 
 	resultPtr := &result
-	if err := mapper.MapValue(reflect.ValueOf(src), reflect.ValueOf(resultPtr).Elem()); err != nil {
+	if err := mapper.MapValueContext(ctx, reflect.ValueOf(src), reflect.ValueOf(resultPtr).Elem(), buildOptions(opts)); err != nil {
 		return result, fmt.Errorf("mapping failed: %w", err)
 	}
 	return result, nil
@@ -38,24 +47,123 @@ func Map[T any](src any) (T, error) {
 // This function modifies the target object in place.
 // Mapping between source and target types is automatically registered on first use.
 // The destination parameter must be a pointer to the target type.
-func MapTo[T any](src any, dst *T) error {
+// Options apply to this call only.
+func MapTo[T any](src any, dst *T, opts ...Option) error {
+	return MapToCtx(context.Background(), src, dst, opts...)
+}
+
+// MapToCtx is like MapTo, passing ctx to the converters, hooks and member resolvers
+// of registered pairs. Mapping stops with ctx's error once ctx is done.
+func MapToCtx[T any](ctx context.Context, src any, dst *T, opts ...Option) error {
 	if src == nil {
-		return fmt.Errorf("source cannot be nil")
+		return ErrNilSource
 	}
 
 	if dst == nil {
-		return fmt.Errorf("destination cannot be nil pointer")
+		return ErrNilDestination
 	}
 
 	// Get types
 	typeCache := cache.GetGlobalCache()
 	sourceType := reflect.TypeOf(src)
-	targetType := reflect.TypeOf(*dst)
+	targetType := typeOf[T]()
 
 	// Auto-register the mapping if not already registered
 	if !typeCache.IsRegistered(sourceType, targetType) {
 		typeCache.RegisterMapping(sourceType, targetType)
 	}
 
-	return mapper.MapValue(reflect.ValueOf(src), reflect.ValueOf(dst).Elem())
+	return mapper.MapValueContext(ctx, reflect.ValueOf(src), reflect.ValueOf(dst).Elem(), buildOptions(opts))
+}
+
+// Clone returns a deep copy of v: pointers, slices, maps, arrays and interface
+// values are copied recursively instead of being shared with v.
+// Use Shared to keep selected types shared.
+func Clone[T any](v T, opts ...Option) (T, error) {
+	var result T
+	options := buildOptions(append([]Option{DeepCopy()}, opts...))
+	if err := mapper.MapValueWithOptions(reflect.ValueOf(&v).Elem(), reflect.ValueOf(&result).Elem(), options); err != nil {
+		return result, fmt.Errorf("mapping failed: %w", err)
+	}
+	return result, nil
+}
+
+// MapSlice maps each element of src to D, resolving the S to D mapping once for all elements.
+// A nil slice maps to nil. Options apply to this call only.
+func MapSlice[S, D any](src []S, opts ...Option) ([]D, error) {
+	if src == nil {
+		return nil, nil
+	}
+
+	dst := make([]D, len(src))
+	plan := mapper.CompilePlan(typeOf[S](), typeOf[D]())
+	if err := plan.MapElements(context.Background(), reflect.ValueOf(src), reflect.ValueOf(dst), buildOptions(opts)); err != nil {
+		return dst, fmt.Errorf("mapping failed: %w", err)
+	}
+	return dst, nil
+}
+
+// MapMapValues maps each value of src to D under the same key, resolving the S to D
+// mapping once for all values. A nil map maps to nil. Options apply to this call only.
+func MapMapValues[K comparable, S, D any](src map[K]S, opts ...Option) (map[K]D, error) {
+	if src == nil {
+		return nil, nil
+	}
+
+	dst := make(map[K]D, len(src))
+	plan := mapper.CompilePlan(typeOf[S](), typeOf[D]())
+	if err := plan.MapValues(context.Background(), reflect.ValueOf(src), reflect.ValueOf(dst), buildOptions(opts)); err != nil {
+		return dst, fmt.Errorf("mapping failed: %w", err)
+	}
+	return dst, nil
+}
+
+// MapPtr maps the value src points to into a new D. A nil pointer maps to nil.
+// Options apply to this call only.
+func MapPtr[S, D any](src *S, opts ...Option) (*D, error) {
+	if src == nil {
+		return nil, nil
+	}
+
+	var dst *D
+	plan := mapper.CompilePlan(typeOf[*S](), typeOf[*D]())
+	if err := plan.MapValue(context.Background(), reflect.ValueOf(src), reflect.ValueOf(&dst).Elem(), buildOptions(opts)); err != nil {
+		return dst, fmt.Errorf("mapping failed: %w", err)
+	}
+	return dst, nil
+}
+
+// MapSliceInto maps each element of src to D into dst, reusing dst's backing array when
+// it has enough capacity, and returns the resulting slice of len(src) elements.
+// Elements of dst are reset before mapping. A dst sharing memory with src is not reused,
+// as resetting it would clear the source. Options apply to this call only.
+func MapSliceInto[S, D any](dst []D, src []S, opts ...Option) ([]D, error) {
+	if cap(dst) < len(src) || overlaps(dst[:len(src)], src) {
+		dst = make([]D, len(src))
+	} else {
+		dst = dst[:len(src)]
+		var zero D
+		for i := range dst {
+			dst[i] = zero
+		}
+	}
+
+	plan := mapper.CompilePlan(typeOf[S](), typeOf[D]())
+	if err := plan.MapElements(context.Background(), reflect.ValueOf(src), reflect.ValueOf(dst), buildOptions(opts)); err != nil {
+		return dst, fmt.Errorf("mapping failed: %w", err)
+	}
+	return dst, nil
+}
+
+// overlaps reports whether the elements of dst and src share memory
+func overlaps[S, D any](dst []D, src []S) bool {
+	if len(dst) == 0 || len(src) == 0 {
+		return false
+	}
+
+	dstStart := uintptr(unsafe.Pointer(&dst[0]))
+	dstEnd := dstStart + uintptr(len(dst))*unsafe.Sizeof(dst[0])
+	srcStart := uintptr(unsafe.Pointer(&src[0]))
+	srcEnd := srcStart + uintptr(len(src))*unsafe.Sizeof(src[0])
+	return srcStart < dstEnd && dstStart < srcEnd
 }
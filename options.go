@@ -0,0 +1,322 @@
+package mapster
+
+import (
+	"sync/atomic"
+
+	"github.com/deferz/go-mapster/internal/mapper"
+)
+
+// Option configures mapping behaviour.
+// Options can be set as defaults with SetDefaults, passed to a single Map or
+// MapTo call, or scoped to a registered pair with MapperConfig.With.
+type Option func(*mapper.Options)
+
+// defaultOptions holds the []Option applied before the options of every call
+var defaultOptions atomic.Value
+
+// SetDefaults sets options applied to every Map and MapTo call.
+// Call and pair options take precedence over the defaults.
+// Calling SetDefaults again replaces the previous defaults.
+func SetDefaults(opts ...Option) {
+	defaultOptions.Store(append([]Option(nil), opts...))
+}
+
+// WeaklyTyped enables lenient conversions for loosely typed sources such as
+// configuration values or JSON-decoded map[string]any:
+//   - integral float64 values to integers
+//   - "1", "true" and numbers to bool, numeric strings to numbers
+//   - numbers and booleans to strings
+//   - a single value to a one-element slice
+//   - comma-separated strings to []string
+//   - empty strings to nil pointers
+func WeaklyTyped() Option {
+	return func(o *mapper.Options) {
+		o.WeaklyTyped = true
+	}
+}
+
+// DuplicateKeyPolicy decides what happens when two slice elements map to the same key
+type DuplicateKeyPolicy = mapper.DuplicateKeyPolicy
+
+const (
+	// DuplicateKeyError fails the mapping on duplicate keys (default)
+	DuplicateKeyError = mapper.DuplicateKeyError
+	// DuplicateKeyKeepFirst keeps the first element with a given key
+	DuplicateKeyKeepFirst = mapper.DuplicateKeyKeepFirst
+	// DuplicateKeyKeepLast keeps the last element with a given key
+	DuplicateKeyKeepLast = mapper.DuplicateKeyKeepLast
+)
+
+// KeyBy selects the map key of each element by a dotted member path, such as
+// "ID" or "Customer.ID", when a slice is mapped to a map.
+func KeyBy(path string) Option {
+	return func(o *mapper.Options) {
+		o.KeyField = path
+	}
+}
+
+// KeyByFunc selects the map key of each element with a function when a slice
+// is mapped to a map. It takes precedence over KeyBy.
+func KeyByFunc(fn func(elem any) (any, error)) Option {
+	return func(o *mapper.Options) {
+		o.KeyFunc = fn
+	}
+}
+
+// OnDuplicateKey sets the policy for elements producing the same map key
+func OnDuplicateKey(policy DuplicateKeyPolicy) Option {
+	return func(o *mapper.Options) {
+		o.DuplicateKeys = policy
+	}
+}
+
+// SortKeysBy orders map entries with less when a map is mapped to a slice.
+// By default keys are sorted in their natural order; maps whose keys have none,
+// such as structs or pointers, fail to map to a slice without SortKeysBy.
+func SortKeysBy(less func(a, b any) bool) Option {
+	return func(o *mapper.Options) {
+		o.KeyLess = less
+	}
+}
+
+// ReplaceCollections replaces target slices with newly mapped ones (default).
+// Use it on a pair or field to override a strategy set for the call.
+func ReplaceCollections() Option {
+	return func(o *mapper.Options) {
+		o.Collection = mapper.CollectionReplace
+	}
+}
+
+// AppendCollections appends mapped source elements to the existing target slices.
+// Fixed-size array targets cannot grow and are replaced instead.
+func AppendCollections() Option {
+	return func(o *mapper.Options) {
+		o.Collection = mapper.CollectionAppend
+	}
+}
+
+// MergeCollectionsByIndex maps each source element onto the existing target element
+// at the same index. Target slices take the source length.
+func MergeCollectionsByIndex() Option {
+	return func(o *mapper.Options) {
+		o.Collection = mapper.CollectionMergeByIndex
+	}
+}
+
+// SyncCollectionsByKey matches target elements with source elements by the identity
+// member at key (a dotted path such as "ID"). Matching elements are updated in place,
+// new source elements are appended, and target elements missing from the source are
+// removed when removeMissing is true. Fixed-size array targets cannot grow or shrink
+// and are replaced instead.
+func SyncCollectionsByKey(key string, removeMissing bool) Option {
+	return func(o *mapper.Options) {
+		o.Collection = mapper.CollectionSyncByKey
+		o.SyncKey = key
+		o.SyncRemoveMissing = removeMissing
+	}
+}
+
+// ReplaceMaps replaces target maps with newly mapped ones (default).
+// Use it on a pair or field to override a merge mode set for the call.
+func ReplaceMaps() Option {
+	return func(o *mapper.Options) {
+		o.Maps = mapper.MapReplace
+		o.MapRemoveMissing = false
+	}
+}
+
+// MergeMaps updates existing target maps in place: entries present in the source
+// overwrite the target entries, other target keys are kept unless removeMissing is true.
+func MergeMaps(removeMissing bool) Option {
+	return func(o *mapper.Options) {
+		o.Maps = mapper.MapMerge
+		o.MapRemoveMissing = removeMissing
+	}
+}
+
+// DeepMergeMaps works like MergeMaps but maps source entries onto the existing
+// target entries, so struct values, pointers and nested maps are merged recursively.
+func DeepMergeMaps(removeMissing bool) Option {
+	return func(o *mapper.Options) {
+		o.Maps = mapper.MapDeepMerge
+		o.MapRemoveMissing = removeMissing
+	}
+}
+
+// IgnoreNil keeps target values when the source member is a nil pointer,
+// interface, map or slice, so partial updates only copy present values.
+// Nested structs and existing pointees are merged instead of replaced.
+func IgnoreNil() Option {
+	return func(o *mapper.Options) {
+		o.IgnoreNil = true
+	}
+}
+
+// IgnoreZero keeps target values when the source member is a zero value.
+// It implies IgnoreNil semantics, since nil values are zero values.
+func IgnoreZero() Option {
+	return func(o *mapper.Options) {
+		o.IgnoreZero = true
+	}
+}
+
+// FieldMask restricts the mapping to the given dotted target paths, such as
+// "name" or "address.city", and their descendants. Segments match field names
+// case-insensitively and ignoring underscores. Paths are validated against the
+// target type and unknown paths are reported as errors. Field masks apply per call.
+func FieldMask(paths ...string) Option {
+	return func(o *mapper.Options) {
+		o.FieldMask = append(o.FieldMask, paths...)
+	}
+}
+
+// DeepCopy copies pointers, slices, maps, arrays and interface values whose source
+// and target types are identical, so the result shares no mutable data with the source.
+// Unexported struct fields are still assigned as they are.
+func DeepCopy() Option {
+	return func(o *mapper.Options) {
+		o.DeepCopy = true
+	}
+}
+
+// Shared keeps values of type T shared with the source under DeepCopy,
+// for types that must not be copied such as *sync.Mutex or loggers.
+func Shared[T any]() Option {
+	return func(o *mapper.Options) {
+		o.SharedTypes = append(o.SharedTypes, typeOf[T]())
+	}
+}
+
+// CyclePolicy decides what happens when the source graph refers back to a value
+// that is still being mapped, such as a Parent pointer back to its owner
+type CyclePolicy = mapper.CyclePolicy
+
+const (
+	// CycleError fails the mapping with the path of the cycle (default)
+	CycleError = mapper.CycleError
+	// CycleBreak leaves the target member closing the cycle nil
+	CycleBreak = mapper.CycleBreak
+	// CyclePreserve reproduces pointer cycles in the target graph
+	CyclePreserve = mapper.CyclePreserve
+)
+
+// OnCycle sets the policy for circular references in the source graph
+func OnCycle(policy CyclePolicy) Option {
+	return func(o *mapper.Options) {
+		o.Cycles = policy
+	}
+}
+
+// PreserveReferences maps every occurrence of a source pointer to the same target
+// pointer within a call, so shared and cyclic references in the source graph are
+// reproduced in the target graph instead of being mapped to separate copies.
+func PreserveReferences() Option {
+	return func(o *mapper.Options) {
+		o.PreserveReferences = true
+	}
+}
+
+// MappingError reports a failure to map a value with the target and source paths
+// of the failing member, such as "Orders[3].Items[1].Price". Use errors.As to inspect it.
+type MappingError = mapper.MappingError
+
+// MappingErrors lists every field-level failure of a mapping made with CollectErrors.
+// It implements Unwrap() []error, so errors.Is and errors.As inspect each failure.
+type MappingErrors = mapper.MappingErrors
+
+// LimitError reports that a mapping exceeded a limit set with MaxDepth,
+// MaxCollectionLen or MaxElements. Use errors.As to inspect it.
+type LimitError = mapper.LimitError
+
+// LimitKind names the limit reported by a LimitError
+type LimitKind = mapper.LimitKind
+
+const (
+	// LimitDepth is the limit set with MaxDepth
+	LimitDepth = mapper.LimitDepth
+	// LimitCollectionLen is the limit set with MaxCollectionLen
+	LimitCollectionLen = mapper.LimitCollectionLen
+	// LimitElements is the limit set with MaxElements
+	LimitElements = mapper.LimitElements
+)
+
+// MaxDepth limits how deeply structs, collections, maps and pointers may nest
+// in the source, protecting against hostile or runaway inputs
+func MaxDepth(n int) Option {
+	return func(o *mapper.Options) {
+		o.MaxDepth = n
+	}
+}
+
+// MaxCollectionLen limits the length of each source slice, array or map
+func MaxCollectionLen(n int) Option {
+	return func(o *mapper.Options) {
+		o.MaxCollectionLen = n
+	}
+}
+
+// MaxElements limits the total number of slice, array and map elements mapped in a call
+func MaxElements(n int) Option {
+	return func(o *mapper.Options) {
+		o.MaxElements = n
+	}
+}
+
+// CollectErrors continues mapping past field-level failures, leaving the failing
+// fields untouched, and returns MappingErrors listing every failure with its path.
+// Limit errors still stop the mapping.
+func CollectErrors() Option {
+	return func(o *mapper.Options) {
+		o.CollectErrors = true
+	}
+}
+
+// PanicError reports a panic raised while mapping, as the Cause of a MappingError
+// locating the member being mapped
+type PanicError = mapper.PanicError
+
+// PropagatePanics lets panics raised while mapping escape from Map and MapTo
+// instead of being returned as a MappingError wrapping a PanicError, for debugging
+func PropagatePanics() Option {
+	return func(o *mapper.Options) {
+		o.PropagatePanics = true
+	}
+}
+
+// ReuseCapacity lets MapTo reuse the backing array of an existing target slice with
+// enough capacity, and the storage of an existing target map, instead of allocating
+// new ones. It applies where collections and maps are replaced: the reused elements
+// are reset before mapping, and other holders of the old slice or map see the change.
+func ReuseCapacity() Option {
+	return func(o *mapper.Options) {
+		o.ReuseCapacity = true
+	}
+}
+
+// Parallel maps the elements of slices and arrays with at least threshold elements
+// concurrently on the given number of workers, preserving element order. A threshold
+// of 0 uses 1024 and workers of 0 use GOMAXPROCS. Mapping stays sequential for
+// collections merged into existing targets and with PreserveReferences, the
+// CyclePreserve policy or MaxElements. On failure the error of the lowest failing
+// element is returned, and CollectErrors reports failures in element order.
+func Parallel(threshold, workers int) Option {
+	return func(o *mapper.Options) {
+		o.Parallel = true
+		o.ParallelThreshold = threshold
+		o.ParallelWorkers = workers
+	}
+}
+
+// buildOptions applies the default and call options to a fresh set of options
+func buildOptions(opts []Option) *mapper.Options {
+	options := &mapper.Options{}
+	if defaults, ok := defaultOptions.Load().([]Option); ok {
+		for _, opt := range defaults {
+			opt(options)
+		}
+	}
+	for _, opt := range opts {
+		opt(options)
+	}
+	return options
+}
@@ -0,0 +1,47 @@
+package mapster
+
+import "sync"
+
+// Pool reuses destination objects of type T across mappings, so mapping in a hot loop
+// does not allocate a new target per call. Objects are reset when returned with Put.
+type Pool[T any] struct {
+	pool  sync.Pool
+	reset func(*T)
+}
+
+// NewPool creates a pool of destination objects. reset prepares an object returned with Put
+// for its next use; a nil reset sets it to the zero value. A reset that truncates slices
+// instead of clearing them lets ReuseCapacity reuse their backing arrays.
+func NewPool[T any](reset func(*T)) *Pool[T] {
+	if reset == nil {
+		reset = func(v *T) {
+			var zero T
+			*v = zero
+		}
+	}
+	return &Pool[T]{reset: reset}
+}
+
+// Map maps src into a destination taken from the pool, as MapTo does.
+// Return the destination with Put once it is no longer used.
+func (p *Pool[T]) Map(src any, opts ...Option) (*T, error) {
+	dst, _ := p.pool.Get().(*T)
+	if dst == nil {
+		dst = new(T)
+	}
+
+	if err := MapTo(src, dst, opts...); err != nil {
+		p.Put(dst)
+		return nil, err
+	}
+	return dst, nil
+}
+
+// Put resets a destination and returns it to the pool
+func (p *Pool[T]) Put(v *T) {
+	if v == nil {
+		return
+	}
+	p.reset(v)
+	p.pool.Put(v)
+}
@@ -0,0 +1,61 @@
+package mapster
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/deferz/go-mapster/internal/mapper"
+)
+
+// MapStream maps each value received from src to D and sends it on the returned channel,
+// resolving the S to D mapping once for the whole stream. Values are sent in order.
+// Mapping stops at the first failure or when ctx is done: the error is sent on the
+// error channel and both channels are closed. Both channels are also closed once src is
+// closed and drained. Mapping failures are MappingErrors whose path starts with the
+// element index, such as "[42].Price". Options apply to each element on its own, so
+// limits such as MaxElements bound every element rather than the whole stream.
+func MapStream[S, D any](ctx context.Context, src <-chan S, opts ...Option) (<-chan D, <-chan error) {
+	out := make(chan D)
+	errs := make(chan error, 1)
+
+	go func() {
+		defer close(errs)
+		defer close(out)
+
+		elements, err := mapper.CompilePlan(typeOf[S](), typeOf[D]()).ElementMapper(ctx, buildOptions(opts))
+		if err != nil {
+			errs <- fmt.Errorf("mapping failed: %w", err)
+			return
+		}
+
+		for i := 0; ; i++ {
+			var value S
+			select {
+			case <-ctx.Done():
+				errs <- ctx.Err()
+				return
+			case received, ok := <-src:
+				if !ok {
+					return
+				}
+				value = received
+			}
+
+			var result D
+			if err := elements.Map(i, reflect.ValueOf(&value).Elem(), reflect.ValueOf(&result).Elem()); err != nil {
+				errs <- fmt.Errorf("mapping failed: %w", err)
+				return
+			}
+
+			select {
+			case <-ctx.Done():
+				errs <- ctx.Err()
+				return
+			case out <- result:
+			}
+		}
+	}()
+
+	return out, errs
+}
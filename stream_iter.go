@@ -0,0 +1,41 @@
+//go:build go1.23
+
+package mapster
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"reflect"
+
+	"github.com/deferz/go-mapster/internal/mapper"
+)
+
+// MapSeq maps each value yielded by seq to D, resolving the S to D mapping once for
+// every iteration. Iteration stops after yielding the first failure with a zero D;
+// mapping failures are MappingErrors whose path starts with the element index, such
+// as "[42].Price". Options apply to each element on its own, so limits such as
+// MaxElements bound every element rather than the whole sequence.
+func MapSeq[S, D any](seq iter.Seq[S], opts ...Option) iter.Seq2[D, error] {
+	plan := mapper.CompilePlan(typeOf[S](), typeOf[D]())
+
+	return func(yield func(D, error) bool) {
+		var zero D
+		elements, err := plan.ElementMapper(context.Background(), buildOptions(opts))
+		if err != nil {
+			yield(zero, fmt.Errorf("mapping failed: %w", err))
+			return
+		}
+
+		i := 0
+		seq(func(value S) bool {
+			var result D
+			if err := elements.Map(i, reflect.ValueOf(&value).Elem(), reflect.ValueOf(&result).Elem()); err != nil {
+				yield(zero, fmt.Errorf("mapping failed: %w", err))
+				return false
+			}
+			i++
+			return yield(result, nil)
+		})
+	}
+}
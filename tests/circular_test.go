@@ -0,0 +1,144 @@
+package tests
+
+import (
+	"strings"
+	"testing"
+
+	mapster "github.com/deferz/go-mapster"
+)
+
+// Types for circular reference tests
+type TreeNode struct {
+	Name     string
+	Parent   *TreeNode
+	Children []*TreeNode
+}
+
+type TreeNodeDTO struct {
+	Name     string
+	Parent   *TreeNodeDTO
+	Children []*TreeNodeDTO
+}
+
+func newTree() *TreeNode {
+	root := &TreeNode{Name: "root"}
+	child := &TreeNode{Name: "child", Parent: root}
+	root.Children = []*TreeNode{child}
+	return root
+}
+
+// TestCircularReferences tests the cycle policies
+func TestCircularReferences(t *testing.T) {
+	t.Run("Error by default", func(t *testing.T) {
+		_, err := mapster.Map[TreeNodeDTO](newTree())
+		if err == nil {
+			t.Fatal("Expected circular reference error, got nil")
+		}
+		if !strings.Contains(err.Error(), "Children[0].Parent") {
+			t.Errorf("Expected cycle path in error, got %v", err)
+		}
+	})
+
+	t.Run("Break", func(t *testing.T) {
+		dst, err := mapster.Map[*TreeNodeDTO](newTree(), mapster.OnCycle(mapster.CycleBreak))
+		if err != nil {
+			t.Fatalf("Map failed: %v", err)
+		}
+		if len(dst.Children) != 1 || dst.Children[0].Name != "child" || dst.Children[0].Parent != nil {
+			t.Errorf("Expected back-link broken, got %+v", dst.Children[0])
+		}
+	})
+
+	t.Run("Preserve", func(t *testing.T) {
+		dst, err := mapster.Map[*TreeNodeDTO](newTree(), mapster.OnCycle(mapster.CyclePreserve))
+		if err != nil {
+			t.Fatalf("Map failed: %v", err)
+		}
+		if dst.Children[0].Parent != dst {
+			t.Errorf("Expected back-link to the mapped root, got %+v", dst.Children[0].Parent)
+		}
+	})
+
+	t.Run("Preserve into a value root", func(t *testing.T) {
+		var dst TreeNodeDTO
+		if err := mapster.MapTo(newTree(), &dst, mapster.OnCycle(mapster.CyclePreserve)); err != nil {
+			t.Fatalf("MapTo failed: %v", err)
+		}
+		if dst.Children[0].Parent != &dst {
+			t.Errorf("Expected back-link to the target root, got %+v", dst.Children[0].Parent)
+		}
+
+		mapped, err := mapster.Map[TreeNodeDTO](newTree(), mapster.OnCycle(mapster.CyclePreserve))
+		if err != nil {
+			t.Fatalf("Map failed: %v", err)
+		}
+		parent := mapped.Children[0].Parent
+		if parent == nil || parent.Name != "root" || parent.Children[0] != mapped.Children[0] {
+			t.Errorf("Expected back-link to the mapped root, got %+v", parent)
+		}
+	})
+
+	t.Run("Self reference", func(t *testing.T) {
+		node := &TreeNode{Name: "loop"}
+		node.Parent = node
+
+		dst, err := mapster.Map[*TreeNodeDTO](node, mapster.OnCycle(mapster.CyclePreserve))
+		if err != nil {
+			t.Fatalf("Map failed: %v", err)
+		}
+		if dst.Parent != dst {
+			t.Error("Expected self reference preserved")
+		}
+	})
+
+	t.Run("Clone", func(t *testing.T) {
+		if _, err := mapster.Clone(newTree()); err == nil {
+			t.Fatal("Expected circular reference error, got nil")
+		}
+
+		dst, err := mapster.Clone(newTree(), mapster.OnCycle(mapster.CyclePreserve))
+		if err != nil {
+			t.Fatalf("Clone failed: %v", err)
+		}
+		if dst.Children[0].Parent != dst {
+			t.Error("Expected cloned back-link to the cloned root")
+		}
+	})
+
+	t.Run("To map", func(t *testing.T) {
+		if _, err := mapster.Map[map[string]any](newTree()); err == nil {
+			t.Fatal("Expected circular reference error, got nil")
+		}
+
+		dst, err := mapster.Map[map[string]any](newTree(), mapster.OnCycle(mapster.CycleBreak))
+		if err != nil {
+			t.Fatalf("Map failed: %v", err)
+		}
+		child := dst["Children"].([]any)[0].(map[string]any)
+		if child["Name"] != "child" || child["Parent"] != nil {
+			t.Errorf("Expected back-link broken, got %v", child)
+		}
+	})
+
+	t.Run("Generic map cycle", func(t *testing.T) {
+		src := map[string]any{"name": "loop"}
+		src["self"] = src
+
+		if _, err := mapster.Map[map[string]any](src, mapster.DeepCopy()); err == nil {
+			t.Fatal("Expected circular reference error, got nil")
+		}
+	})
+
+	t.Run("Shared references are not cycles", func(t *testing.T) {
+		shared := &TreeNode{Name: "shared"}
+		src := &TreeNode{Name: "root", Children: []*TreeNode{shared, shared}}
+
+		dst, err := mapster.Map[*TreeNodeDTO](src)
+		if err != nil {
+			t.Fatalf("Map failed: %v", err)
+		}
+		if len(dst.Children) != 2 || dst.Children[1].Name != "shared" {
+			t.Errorf("Expected both children mapped, got %+v", dst.Children)
+		}
+	})
+}
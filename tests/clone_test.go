@@ -0,0 +1,131 @@
+package tests
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	mapster "github.com/deferz/go-mapster"
+)
+
+// Types for deep clone tests
+type CloneNode struct {
+	Name     string
+	Tags     []string
+	Attrs    map[string]*CloneNode
+	Children []*CloneNode
+	Value    any
+	Matrix   [2][]int
+	Created  time.Time
+	Lock     *sync.Mutex
+}
+
+type CloneHolder struct {
+	Node *CloneNode
+}
+
+type CloneHolderDTO struct {
+	Node *CloneNode
+}
+
+func newCloneNode() CloneNode {
+	return CloneNode{
+		Name:     "root",
+		Tags:     []string{"a"},
+		Attrs:    map[string]*CloneNode{"x": {Name: "x"}},
+		Children: []*CloneNode{{Name: "child", Tags: []string{"b"}}},
+		Value:    []int{1},
+		Matrix:   [2][]int{{1}, {2}},
+		Created:  time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		Lock:     &sync.Mutex{},
+	}
+}
+
+// TestClone tests deep copies of identical types
+func TestClone(t *testing.T) {
+	t.Run("Copies references", func(t *testing.T) {
+		src := newCloneNode()
+		dst, err := mapster.Clone(src)
+		if err != nil {
+			t.Fatalf("Clone failed: %v", err)
+		}
+
+		dst.Tags[0] = "changed"
+		dst.Attrs["x"].Name = "changed"
+		dst.Children[0].Tags[0] = "changed"
+		dst.Value.([]int)[0] = 9
+		dst.Matrix[1][0] = 9
+
+		if src.Tags[0] != "a" || src.Attrs["x"].Name != "x" || src.Children[0].Tags[0] != "b" {
+			t.Errorf("Expected source untouched, got %+v", src)
+		}
+		if src.Value.([]int)[0] != 1 || src.Matrix[1][0] != 2 {
+			t.Errorf("Expected interface and array values copied, got %v %v", src.Value, src.Matrix)
+		}
+		if dst.Lock == src.Lock {
+			t.Error("Expected mutex pointer copied")
+		}
+		if !dst.Created.Equal(src.Created) {
+			t.Errorf("Expected time kept, got %v", dst.Created)
+		}
+	})
+
+	t.Run("Shared types", func(t *testing.T) {
+		src := newCloneNode()
+		dst, err := mapster.Clone(src, mapster.Shared[*sync.Mutex]())
+		if err != nil {
+			t.Fatalf("Clone failed: %v", err)
+		}
+		if dst.Lock != src.Lock {
+			t.Error("Expected mutex pointer shared")
+		}
+		if &dst.Tags[0] == &src.Tags[0] {
+			t.Error("Expected other references copied")
+		}
+	})
+
+	t.Run("Nil values", func(t *testing.T) {
+		var src *CloneNode
+		dst, err := mapster.Clone(src)
+		if err != nil {
+			t.Fatalf("Clone failed: %v", err)
+		}
+		if dst != nil {
+			t.Errorf("Expected nil, got %+v", dst)
+		}
+
+		empty, err := mapster.Clone(CloneNode{})
+		if err != nil {
+			t.Fatalf("Clone failed: %v", err)
+		}
+		if empty.Tags != nil || empty.Attrs != nil {
+			t.Errorf("Expected nil collections kept nil, got %+v", empty)
+		}
+	})
+
+	t.Run("Map shares by default", func(t *testing.T) {
+		node := newCloneNode()
+		dst, err := mapster.Map[CloneHolderDTO](CloneHolder{Node: &node})
+		if err != nil {
+			t.Fatalf("Map failed: %v", err)
+		}
+		if dst.Node != &node {
+			t.Error("Expected identical field types shared without DeepCopy")
+		}
+	})
+
+	t.Run("Map with DeepCopy", func(t *testing.T) {
+		node := newCloneNode()
+		dst, err := mapster.Map[CloneHolderDTO](CloneHolder{Node: &node}, mapster.DeepCopy())
+		if err != nil {
+			t.Fatalf("Map failed: %v", err)
+		}
+		if dst.Node == &node || dst.Node.Name != "root" {
+			t.Errorf("Expected copied node, got %+v", dst.Node)
+		}
+		dst.Node.Children[0].Name = "changed"
+		if node.Children[0].Name != "child" {
+			t.Error("Expected nested pointers copied")
+		}
+	})
+}
@@ -0,0 +1,149 @@
+package tests
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	mapster "github.com/deferz/go-mapster"
+)
+
+// Types for error aggregation tests
+type CollectSource struct {
+	Name  string
+	Age   any
+	Score any
+	Tags  []any
+}
+
+type CollectTarget struct {
+	Name  string
+	Age   int
+	Score float64
+	Tags  []int
+}
+
+type CollectPointerSource struct {
+	Name  string
+	Count *string
+}
+
+type CollectPointerTarget struct {
+	Name  string
+	Count *int
+}
+
+// TestCollectErrors tests reporting every failing field in one pass
+func TestCollectErrors(t *testing.T) {
+	src := CollectSource{Name: "Alice", Age: "x", Score: 9.5, Tags: []any{1, "y", 3}}
+
+	t.Run("Stops at first failure by default", func(t *testing.T) {
+		_, err := mapster.Map[CollectTarget](src)
+		if err == nil {
+			t.Fatal("Expected error, got nil")
+		}
+
+		var errs mapster.MappingErrors
+		if errors.As(err, &errs) {
+			t.Errorf("Expected a single error, got %v", errs)
+		}
+	})
+
+	t.Run("Collects every failure", func(t *testing.T) {
+		dst, err := mapster.Map[CollectTarget](src, mapster.CollectErrors())
+		if err == nil {
+			t.Fatal("Expected error, got nil")
+		}
+		if !strings.HasPrefix(err.Error(), "mapping failed: 2 mapping errors: ") {
+			t.Errorf("Expected aggregated message, got %v", err)
+		}
+
+		var errs mapster.MappingErrors
+		if !errors.As(err, &errs) {
+			t.Fatalf("Expected MappingErrors, got %v", err)
+		}
+		if len(errs) != 2 {
+			t.Fatalf("Expected 2 errors, got %d: %v", len(errs), errs)
+		}
+		if errs[0].DstPath != "Age" || errs[1].DstPath != "Tags[1]" {
+			t.Errorf("Expected paths Age and Tags[1], got %s and %s", errs[0].DstPath, errs[1].DstPath)
+		}
+
+		// Other fields are still mapped
+		if dst.Name != "Alice" || dst.Score != 9.5 {
+			t.Errorf("Expected Name Alice and Score 9.5, got %s and %v", dst.Name, dst.Score)
+		}
+		if dst.Age != 0 {
+			t.Errorf("Expected failing field to stay zero, got %d", dst.Age)
+		}
+	})
+
+	t.Run("First failure through errors.As", func(t *testing.T) {
+		_, err := mapster.Map[CollectTarget](src, mapster.CollectErrors())
+
+		var mappingErr *mapster.MappingError
+		if !errors.As(err, &mappingErr) {
+			t.Fatalf("Expected MappingError, got %v", err)
+		}
+		if mappingErr.DstPath != "Age" {
+			t.Errorf("Expected first failure at Age, got %s", mappingErr.DstPath)
+		}
+	})
+
+	t.Run("MapTo leaves failing fields untouched", func(t *testing.T) {
+		dst := CollectTarget{Name: "Bob", Age: 40}
+		err := mapster.MapTo(src, &dst, mapster.CollectErrors())
+		if err == nil {
+			t.Fatal("Expected error, got nil")
+		}
+		if dst.Age != 40 {
+			t.Errorf("Expected Age to stay 40, got %d", dst.Age)
+		}
+		if dst.Name != "Alice" {
+			t.Errorf("Expected Name Alice, got %s", dst.Name)
+		}
+	})
+
+	t.Run("Nil pointer left untouched", func(t *testing.T) {
+		count := "x"
+		dst := CollectPointerTarget{}
+		err := mapster.MapTo(CollectPointerSource{Name: "Alice", Count: &count}, &dst, mapster.CollectErrors())
+		if err == nil {
+			t.Fatal("Expected error, got nil")
+		}
+		if dst.Count != nil {
+			t.Errorf("Expected Count to stay nil, got %d", *dst.Count)
+		}
+		if dst.Name != "Alice" {
+			t.Errorf("Expected Name Alice, got %s", dst.Name)
+		}
+	})
+
+	t.Run("Map source", func(t *testing.T) {
+		dst, err := mapster.Map[CollectTarget](map[string]any{"Name": "Carol", "Age": "x", "Score": "y"}, mapster.CollectErrors())
+
+		var errs mapster.MappingErrors
+		if !errors.As(err, &errs) || len(errs) != 2 {
+			t.Fatalf("Expected 2 errors, got %v", err)
+		}
+		if dst.Name != "Carol" {
+			t.Errorf("Expected Name Carol, got %s", dst.Name)
+		}
+	})
+
+	t.Run("No failures", func(t *testing.T) {
+		_, err := mapster.Map[CollectTarget](CollectSource{Name: "Dan", Age: 3}, mapster.CollectErrors())
+		if err != nil {
+			t.Errorf("Expected no error, got %v", err)
+		}
+	})
+
+	t.Run("Limits still stop", func(t *testing.T) {
+		_, err := mapster.Map[CollectTarget](src, mapster.CollectErrors(), mapster.MaxCollectionLen(2))
+
+		var limitErr *mapster.LimitError
+		if !errors.As(err, &limitErr) {
+			t.Errorf("Expected LimitError, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,138 @@
+package tests
+
+import (
+	"testing"
+
+	mapster "github.com/deferz/go-mapster"
+)
+
+// Types for collection strategy tests
+type LineItem struct {
+	SKU      string
+	Quantity int
+}
+
+type LineItemState struct {
+	SKU      string
+	Quantity int
+	Reserved bool // state held only by the target
+}
+
+type Cart struct {
+	Items []LineItem
+}
+
+type CartState struct {
+	Items []*LineItemState `mapster:",sync=SKU,prune"`
+}
+
+type CartLog struct {
+	Items []LineItem `mapster:",append"`
+}
+
+// TestCollectionStrategies tests combining source collections with existing targets
+func TestCollectionStrategies(t *testing.T) {
+	t.Run("Replace by default", func(t *testing.T) {
+		dst := []LineItem{{SKU: "old"}}
+		if err := mapster.MapTo([]LineItem{{SKU: "new"}}, &dst); err != nil {
+			t.Fatalf("MapTo failed: %v", err)
+		}
+		if len(dst) != 1 || dst[0].SKU != "new" {
+			t.Errorf("Expected replaced slice, got %+v", dst)
+		}
+	})
+
+	t.Run("Append", func(t *testing.T) {
+		dst := []LineItem{{SKU: "a"}}
+		if err := mapster.MapTo([]LineItem{{SKU: "b"}, {SKU: "c"}}, &dst, mapster.AppendCollections()); err != nil {
+			t.Fatalf("MapTo failed: %v", err)
+		}
+		if len(dst) != 3 || dst[0].SKU != "a" || dst[2].SKU != "c" {
+			t.Errorf("Expected appended slice, got %+v", dst)
+		}
+	})
+
+	t.Run("Merge by index", func(t *testing.T) {
+		first := &LineItemState{SKU: "a", Reserved: true}
+		dst := []*LineItemState{first, {SKU: "b"}}
+		src := []LineItem{{SKU: "a", Quantity: 5}}
+
+		if err := mapster.MapTo(src, &dst, mapster.MergeCollectionsByIndex()); err != nil {
+			t.Fatalf("MapTo failed: %v", err)
+		}
+		if len(dst) != 1 || dst[0] != first {
+			t.Fatalf("Expected the existing element to be kept, got %+v", dst)
+		}
+		if first.Quantity != 5 || !first.Reserved {
+			t.Errorf("Expected merged element, got %+v", first)
+		}
+	})
+
+	t.Run("Sync by key", func(t *testing.T) {
+		keep := &LineItemState{SKU: "a", Quantity: 1, Reserved: true}
+		gone := &LineItemState{SKU: "b", Quantity: 1}
+		dst := []*LineItemState{keep, gone}
+		src := []LineItem{{SKU: "c", Quantity: 3}, {SKU: "a", Quantity: 2}}
+
+		if err := mapster.MapTo(src, &dst, mapster.SyncCollectionsByKey("SKU", false)); err != nil {
+			t.Fatalf("MapTo failed: %v", err)
+		}
+		if len(dst) != 3 || dst[0] != keep || dst[1] != gone || dst[2].SKU != "c" {
+			t.Fatalf("Expected [a b c] with existing elements kept, got %+v", dst)
+		}
+		if keep.Quantity != 2 || !keep.Reserved {
+			t.Errorf("Expected element a updated in place, got %+v", keep)
+		}
+	})
+
+	t.Run("Sync by key from field tag", func(t *testing.T) {
+		keep := &LineItemState{SKU: "a", Reserved: true}
+		dst := CartState{Items: []*LineItemState{keep, {SKU: "b"}}}
+		src := Cart{Items: []LineItem{{SKU: "a", Quantity: 4}, {SKU: "c", Quantity: 1}}}
+
+		if err := mapster.MapTo(src, &dst); err != nil {
+			t.Fatalf("MapTo failed: %v", err)
+		}
+		if len(dst.Items) != 2 || dst.Items[0] != keep || dst.Items[1].SKU != "c" {
+			t.Fatalf("Expected [a c] with b pruned, got %+v", dst.Items)
+		}
+		if keep.Quantity != 4 || !keep.Reserved {
+			t.Errorf("Expected element a updated in place, got %+v", keep)
+		}
+	})
+
+	t.Run("Append from field tag", func(t *testing.T) {
+		dst := CartLog{Items: []LineItem{{SKU: "a"}}}
+		if err := mapster.MapTo(Cart{Items: []LineItem{{SKU: "b"}}}, &dst); err != nil {
+			t.Fatalf("MapTo failed: %v", err)
+		}
+		if len(dst.Items) != 2 || dst.Items[1].SKU != "b" {
+			t.Errorf("Expected appended items, got %+v", dst.Items)
+		}
+	})
+
+	t.Run("Missing sync key", func(t *testing.T) {
+		dst := []LineItemState{{SKU: "a"}}
+		if err := mapster.MapTo([]LineItem{{SKU: "a"}}, &dst, mapster.SyncCollectionsByKey("ID", false)); err == nil {
+			t.Fatal("Expected error for unknown sync key, got nil")
+		}
+	})
+
+	t.Run("Array targets replaced", func(t *testing.T) {
+		log := [2]LineItem{{SKU: "a"}, {SKU: "b"}}
+		if err := mapster.MapTo([]LineItem{{SKU: "c"}}, &log, mapster.AppendCollections()); err != nil {
+			t.Fatalf("MapTo failed: %v", err)
+		}
+		if log != [2]LineItem{{SKU: "c"}} {
+			t.Errorf("Expected the array replaced when appending, got %+v", log)
+		}
+
+		states := [2]LineItemState{{SKU: "a", Reserved: true}, {SKU: "b"}}
+		if err := mapster.MapTo([]LineItem{{SKU: "a", Quantity: 5}}, &states, mapster.SyncCollectionsByKey("SKU", false)); err != nil {
+			t.Fatalf("MapTo failed: %v", err)
+		}
+		if states != [2]LineItemState{{SKU: "a", Quantity: 5}} {
+			t.Errorf("Expected the array replaced when syncing, got %+v", states)
+		}
+	})
+}
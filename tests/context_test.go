@@ -0,0 +1,228 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	mapster "github.com/deferz/go-mapster"
+)
+
+// Types for context-aware mapping tests
+type ctxKey string
+
+type CtxMoney struct {
+	Cents int64
+}
+
+type CtxUser struct {
+	Name  string
+	Email string
+	Price CtxMoney
+}
+
+type CtxUserDTO struct {
+	Name   string
+	Email  string
+	Price  string
+	Tenant string
+	Mapped bool
+}
+
+func init() {
+	mapster.NewMapperConfig[CtxMoney, string]().
+		ConvertUsing(func(ctx context.Context, src CtxMoney) (string, error) {
+			if ctx.Value(ctxKey("locale")) == "de" {
+				return strings.Replace(formatCents(src.Cents), ".", ",", 1), nil
+			}
+			return formatCents(src.Cents), nil
+		}).
+		Register()
+
+	mapster.NewMapperConfig[CtxUser, CtxUserDTO]().
+		ForMember("Tenant", func(ctx context.Context, src CtxUser) (any, error) {
+			tenant := ctx.Value(ctxKey("tenant"))
+			if tenant == "forbidden" {
+				return nil, errors.New("tenant not allowed")
+			}
+			return tenant, nil
+		}).
+		ForMember("Email", func(ctx context.Context, src CtxUser) (any, error) {
+			if ctx.Value(ctxKey("redact")) == true {
+				return "***", nil
+			}
+			return src.Email, nil
+		}).
+		AfterMap(func(ctx context.Context, src CtxUser, dst *CtxUserDTO) error {
+			dst.Mapped = true
+			return nil
+		}).
+		Register()
+}
+
+// Types for same-type pair tests
+type CtxSecret struct {
+	Value string
+}
+
+type CtxAccount struct {
+	Name    string
+	Secret  CtxSecret
+	Secrets []CtxSecret
+}
+
+func init() {
+	mapster.NewMapperConfig[CtxSecret, CtxSecret]().
+		ConvertUsing(func(ctx context.Context, src CtxSecret) (CtxSecret, error) {
+			return CtxSecret{Value: "***"}, nil
+		}).
+		Register()
+}
+
+func formatCents(cents int64) string {
+	return fmt.Sprintf("%d.%02d", cents/100, cents%100)
+}
+
+// TestMapCtx tests passing a context to converters, hooks and member resolvers
+func TestMapCtx(t *testing.T) {
+	src := CtxUser{Name: "Ann", Email: "ann@example.com", Price: CtxMoney{Cents: 1250}}
+
+	t.Run("Background context", func(t *testing.T) {
+		dst, err := mapster.Map[CtxUserDTO](src)
+		if err != nil {
+			t.Fatalf("Map failed: %v", err)
+		}
+		if dst.Price != "12.50" {
+			t.Errorf("Expected Price 12.50, got %s", dst.Price)
+		}
+		if dst.Email != "ann@example.com" || dst.Tenant != "" {
+			t.Errorf("Expected Email ann@example.com and no tenant, got %s and %s", dst.Email, dst.Tenant)
+		}
+		if !dst.Mapped {
+			t.Error("Expected AfterMap hook to run")
+		}
+	})
+
+	t.Run("Context values", func(t *testing.T) {
+		ctx := context.WithValue(context.Background(), ctxKey("locale"), "de")
+		ctx = context.WithValue(ctx, ctxKey("tenant"), "acme")
+		ctx = context.WithValue(ctx, ctxKey("redact"), true)
+
+		dst, err := mapster.MapCtx[CtxUserDTO](ctx, src)
+		if err != nil {
+			t.Fatalf("MapCtx failed: %v", err)
+		}
+		if dst.Price != "12,50" {
+			t.Errorf("Expected Price 12,50, got %s", dst.Price)
+		}
+		if dst.Tenant != "acme" {
+			t.Errorf("Expected Tenant acme, got %s", dst.Tenant)
+		}
+		if dst.Email != "***" {
+			t.Errorf("Expected redacted Email, got %s", dst.Email)
+		}
+	})
+
+	t.Run("MapToCtx existing target", func(t *testing.T) {
+		ctx := context.WithValue(context.Background(), ctxKey("tenant"), "acme")
+
+		var dst CtxUserDTO
+		if err := mapster.MapToCtx(ctx, src, &dst); err != nil {
+			t.Fatalf("MapToCtx failed: %v", err)
+		}
+		if dst.Tenant != "acme" || dst.Name != "Ann" {
+			t.Errorf("Expected Tenant acme and Name Ann, got %s and %s", dst.Tenant, dst.Name)
+		}
+	})
+
+	t.Run("Resolver error", func(t *testing.T) {
+		ctx := context.WithValue(context.Background(), ctxKey("tenant"), "forbidden")
+
+		_, err := mapster.MapCtx[CtxUserDTO](ctx, src)
+
+		var mappingErr *mapster.MappingError
+		if !errors.As(err, &mappingErr) {
+			t.Fatalf("Expected MappingError, got %v", err)
+		}
+		if mappingErr.DstPath != "Tenant" {
+			t.Errorf("Expected path Tenant, got %s", mappingErr.DstPath)
+		}
+	})
+
+	t.Run("Nested in collection", func(t *testing.T) {
+		ctx := context.WithValue(context.Background(), ctxKey("tenant"), "acme")
+
+		dst, err := mapster.MapCtx[[]CtxUserDTO](ctx, []CtxUser{src, src})
+		if err != nil {
+			t.Fatalf("MapCtx failed: %v", err)
+		}
+		if dst[1].Tenant != "acme" || !dst[1].Mapped {
+			t.Errorf("Expected mapped element with Tenant acme, got %+v", dst[1])
+		}
+	})
+
+	t.Run("Cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, err := mapster.MapCtx[[]int64](ctx, []int32{1, 2, 3})
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("Expected context.Canceled, got %v", err)
+		}
+	})
+
+	t.Run("Cancelled during collection", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		calls := 0
+		keyBy := mapster.KeyByFunc(func(elem any) (any, error) {
+			calls++
+			if calls == 100 {
+				cancel()
+			}
+			return elem.(int), nil
+		})
+
+		src := make([]int, 1000)
+		for i := range src {
+			src[i] = i
+		}
+
+		_, err := mapster.MapCtx[map[int]int](ctx, src, keyBy, mapster.CollectErrors())
+		if !errors.Is(err, context.Canceled) {
+			t.Fatalf("Expected context.Canceled, got %v", err)
+		}
+		if calls >= len(src) {
+			t.Errorf("Expected mapping to stop early, got %d calls", calls)
+		}
+	})
+
+	t.Run("Same-type pairs", func(t *testing.T) {
+		secret, err := mapster.Map[CtxSecret](CtxSecret{Value: "pw"})
+		if err != nil {
+			t.Fatalf("Map failed: %v", err)
+		}
+		if secret.Value != "***" {
+			t.Errorf("Expected converter applied, got %+v", secret)
+		}
+
+		account := CtxAccount{Name: "Ann", Secret: CtxSecret{Value: "pw"}, Secrets: []CtxSecret{{Value: "a"}, {Value: "b"}}}
+		mapped, err := mapster.Map[CtxAccount](account)
+		if err != nil {
+			t.Fatalf("Map failed: %v", err)
+		}
+		cloned, err := mapster.Clone(account)
+		if err != nil {
+			t.Fatalf("Clone failed: %v", err)
+		}
+		for _, dst := range []CtxAccount{mapped, cloned} {
+			if dst.Name != "Ann" || dst.Secret.Value != "***" || len(dst.Secrets) != 2 || dst.Secrets[1].Value != "***" {
+				t.Errorf("Expected converter applied to nested values, got %+v", dst)
+			}
+		}
+		if account.Secret.Value != "pw" {
+			t.Errorf("Expected source untouched, got %+v", account)
+		}
+	})
+}
@@ -0,0 +1,143 @@
+package tests
+
+import (
+	"testing"
+
+	mapster "github.com/deferz/go-mapster"
+)
+
+// Types for field mask tests
+type MaskAddress struct {
+	City       string
+	PostalCode string
+}
+
+type MaskAudit struct {
+	UpdatedBy string
+}
+
+type MaskUser struct {
+	MaskAudit
+	Name    string
+	Email   string
+	Address *MaskAddress
+	Phones  []MaskAddress
+}
+
+type MaskItem struct {
+	Name string
+	Note string
+}
+
+type MaskOrder struct {
+	Items []MaskItem
+	Tags  map[string]MaskItem
+}
+
+// TestFieldMask tests restricting the mapping to selected paths
+func TestFieldMask(t *testing.T) {
+	existing := func() MaskUser {
+		return MaskUser{
+			MaskAudit: MaskAudit{UpdatedBy: "system"},
+			Name:      "Ann",
+			Email:     "ann@example.com",
+			Address:   &MaskAddress{City: "Berlin", PostalCode: "10115"},
+		}
+	}
+	update := MaskUser{
+		MaskAudit: MaskAudit{UpdatedBy: "bob"},
+		Name:      "Bob",
+		Email:     "bob@example.com",
+		Address:   &MaskAddress{City: "Paris", PostalCode: "75001"},
+	}
+
+	t.Run("Top-level and nested paths", func(t *testing.T) {
+		dst := existing()
+		address := dst.Address
+		if err := mapster.MapTo(update, &dst, mapster.FieldMask("name", "address.city")); err != nil {
+			t.Fatalf("MapTo failed: %v", err)
+		}
+		if dst.Name != "Bob" || dst.Email != "ann@example.com" || dst.UpdatedBy != "system" {
+			t.Errorf("Expected only Name updated, got %+v", dst)
+		}
+		if dst.Address != address || address.City != "Paris" || address.PostalCode != "10115" {
+			t.Errorf("Expected only Address.City updated, got %+v", dst.Address)
+		}
+	})
+
+	t.Run("Whole subtree", func(t *testing.T) {
+		dst := existing()
+		if err := mapster.MapTo(update, &dst, mapster.FieldMask("Address", "address.city")); err != nil {
+			t.Fatalf("MapTo failed: %v", err)
+		}
+		if dst.Address.City != "Paris" || dst.Address.PostalCode != "75001" || dst.Name != "Ann" {
+			t.Errorf("Expected whole Address updated, got %+v", dst)
+		}
+	})
+
+	t.Run("Snake case and promoted fields", func(t *testing.T) {
+		dst := existing()
+		if err := mapster.MapTo(update, &dst, mapster.FieldMask("address.postal_code", "updated_by")); err != nil {
+			t.Fatalf("MapTo failed: %v", err)
+		}
+		if dst.Address.PostalCode != "75001" || dst.Address.City != "Berlin" || dst.UpdatedBy != "bob" {
+			t.Errorf("Expected PostalCode and UpdatedBy updated, got %+v %+v", dst, dst.Address)
+		}
+	})
+
+	t.Run("Map source", func(t *testing.T) {
+		dst := existing()
+		src := map[string]any{"Name": "Carl", "Email": "carl@example.com"}
+		if err := mapster.MapTo(src, &dst, mapster.FieldMask("email")); err != nil {
+			t.Fatalf("MapTo failed: %v", err)
+		}
+		if dst.Name != "Ann" || dst.Email != "carl@example.com" {
+			t.Errorf("Expected only Email updated, got %+v", dst)
+		}
+	})
+
+	t.Run("Map", func(t *testing.T) {
+		dst, err := mapster.Map[MaskUser](update, mapster.FieldMask("name"))
+		if err != nil {
+			t.Fatalf("Map failed: %v", err)
+		}
+		if dst.Name != "Bob" || dst.Email != "" || dst.Address != nil {
+			t.Errorf("Expected only Name mapped, got %+v", dst)
+		}
+	})
+
+	t.Run("Path through a slice", func(t *testing.T) {
+		dst := MaskOrder{Items: []MaskItem{{Name: "old", Note: "keepme"}}}
+		src := MaskOrder{Items: []MaskItem{{Name: "new", Note: "srcnote"}, {Name: "added", Note: "srcnote"}}}
+		if err := mapster.MapTo(src, &dst, mapster.FieldMask("items.name")); err != nil {
+			t.Fatalf("MapTo failed: %v", err)
+		}
+		expected := []MaskItem{{Name: "new", Note: "keepme"}, {Name: "added"}}
+		if len(dst.Items) != 2 || dst.Items[0] != expected[0] || dst.Items[1] != expected[1] {
+			t.Errorf("Expected %+v, got %+v", expected, dst.Items)
+		}
+	})
+
+	t.Run("Path through a map", func(t *testing.T) {
+		dst := MaskOrder{Tags: map[string]MaskItem{"a": {Name: "old", Note: "keepme"}, "b": {Name: "kept"}}}
+		src := MaskOrder{Tags: map[string]MaskItem{"a": {Name: "new", Note: "srcnote"}}}
+		if err := mapster.MapTo(src, &dst, mapster.FieldMask("tags.name")); err != nil {
+			t.Fatalf("MapTo failed: %v", err)
+		}
+		if len(dst.Tags) != 2 || dst.Tags["a"] != (MaskItem{Name: "new", Note: "keepme"}) || dst.Tags["b"].Name != "kept" {
+			t.Errorf("Expected only Name of the existing entry updated, got %+v", dst.Tags)
+		}
+	})
+
+	t.Run("Unknown path", func(t *testing.T) {
+		dst := existing()
+		for _, path := range []string{"nickname", "address.street", "name.first", "phones.zip"} {
+			if err := mapster.MapTo(update, &dst, mapster.FieldMask(path)); err == nil {
+				t.Errorf("Expected error for unknown path %q, got nil", path)
+			}
+		}
+		if dst.Name != "Ann" {
+			t.Errorf("Expected target untouched after invalid mask, got %+v", dst)
+		}
+	})
+}
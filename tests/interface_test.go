@@ -0,0 +1,90 @@
+package tests
+
+import (
+	"testing"
+
+	mapster "github.com/deferz/go-mapster"
+)
+
+// TestInterfaceSourceMapping tests mapping from interface-typed source values
+func TestInterfaceSourceMapping(t *testing.T) {
+	t.Run("Slice of any to slice of structs", func(t *testing.T) {
+		src := []any{
+			User{ID: 1, Name: "User 1"},
+			&User{ID: 2, Name: "User 2"},
+		}
+
+		dst, err := mapster.Map[[]Item](src)
+		if err != nil {
+			t.Fatalf("Map failed: %v", err)
+		}
+
+		if len(dst) != len(src) {
+			t.Fatalf("Expected slice length %d, got %d", len(src), len(dst))
+		}
+		if dst[0].ID != 1 || dst[0].Name != "User 1" {
+			t.Errorf("Expected {1 User 1} at index 0, got %+v", dst[0])
+		}
+		if dst[1].ID != 2 || dst[1].Name != "User 2" {
+			t.Errorf("Expected {2 User 2} at index 1, got %+v", dst[1])
+		}
+	})
+
+	t.Run("Map of any to map of structs", func(t *testing.T) {
+		src := map[string]any{
+			"user1": User{ID: 1, Name: "User 1"},
+			"user2": User{ID: 2, Name: "User 2"},
+		}
+
+		dst, err := mapster.Map[map[string]Item](src)
+		if err != nil {
+			t.Fatalf("Map failed: %v", err)
+		}
+
+		if len(dst) != len(src) {
+			t.Fatalf("Expected map length %d, got %d", len(src), len(dst))
+		}
+		if dst["user2"].ID != 2 || dst["user2"].Name != "User 2" {
+			t.Errorf("Expected {2 User 2} for key user2, got %+v", dst["user2"])
+		}
+	})
+
+	t.Run("Interface field", func(t *testing.T) {
+		type Envelope struct {
+			Payload any
+			Extra   any
+		}
+		type TypedEnvelope struct {
+			Payload Item
+			Extra   *Item
+		}
+
+		src := Envelope{Payload: User{ID: 7, Name: "Payload"}}
+
+		dst, err := mapster.Map[TypedEnvelope](src)
+		if err != nil {
+			t.Fatalf("Map failed: %v", err)
+		}
+
+		if dst.Payload.ID != 7 || dst.Payload.Name != "Payload" {
+			t.Errorf("Expected {7 Payload}, got %+v", dst.Payload)
+		}
+		// A nil interface leaves the target untouched
+		if dst.Extra != nil {
+			t.Errorf("Expected nil Extra, got %+v", dst.Extra)
+		}
+	})
+
+	t.Run("Concrete value to interface target", func(t *testing.T) {
+		src := []User{{ID: 1, Name: "User 1"}}
+
+		dst, err := mapster.Map[[]any](src)
+		if err != nil {
+			t.Fatalf("Map failed: %v", err)
+		}
+
+		if user, ok := dst[0].(User); !ok || user.ID != 1 {
+			t.Errorf("Expected User{ID: 1} at index 0, got %#v", dst[0])
+		}
+	})
+}
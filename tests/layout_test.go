@@ -0,0 +1,170 @@
+package tests
+
+import (
+	"context"
+	"testing"
+
+	mapster "github.com/deferz/go-mapster"
+)
+
+// Types for layout copy tests
+type LayoutPoint struct {
+	X, Y float64
+}
+
+type LayoutEntity struct {
+	ID     int64
+	Name   string
+	Age    int32
+	Active bool
+	Pos    LayoutPoint
+	Scores [3]int16
+}
+
+type LayoutEntityDTO struct {
+	ID     int64
+	Name   string
+	Age    int32
+	Active bool
+	Pos    LayoutPoint
+	Scores [3]int16
+}
+
+type LayoutPartialDTO struct {
+	Age    int32
+	Active bool
+	Pos    LayoutPoint
+	ID     int
+	Name   string
+	Extra  int
+	Tags   []string
+	hidden int
+}
+
+type LayoutPartial struct {
+	ID     int64
+	Name   string
+	Age    int32
+	Active bool
+	Pos    LayoutPoint
+	Tags   []string
+	hidden int
+}
+
+type LayoutTagged struct {
+	ID   int64
+	Name string `mapster:",omitzero"`
+}
+
+type LayoutHooked struct {
+	ID   int64
+	Name string
+}
+
+type LayoutHookedDTO struct {
+	ID   int64
+	Name string
+}
+
+func init() {
+	mapster.NewMapperConfig[LayoutHooked, LayoutHookedDTO]().
+		ForMember("Name", func(ctx context.Context, src LayoutHooked) (any, error) {
+			return "resolved " + src.Name, nil
+		}).
+		Register()
+}
+
+func newLayoutEntity() LayoutEntity {
+	return LayoutEntity{ID: 7, Name: "Ann", Age: 30, Active: true, Pos: LayoutPoint{1, 2}, Scores: [3]int16{4, 5, 6}}
+}
+
+// TestLayoutCopy tests copying layout-identical fields as memory
+func TestLayoutCopy(t *testing.T) {
+	src := newLayoutEntity()
+
+	t.Run("Identical layout", func(t *testing.T) {
+		dst, err := mapster.Map[LayoutEntityDTO](src)
+		if err != nil {
+			t.Fatalf("Map failed: %v", err)
+		}
+		if LayoutEntity(dst) != src {
+			t.Errorf("Expected %+v, got %+v", src, dst)
+		}
+	})
+
+	t.Run("Identical layout in addressable elements", func(t *testing.T) {
+		dst, err := mapster.MapSlice[LayoutEntity, LayoutEntityDTO]([]LayoutEntity{src, {ID: 8}})
+		if err != nil {
+			t.Fatalf("MapSlice failed: %v", err)
+		}
+		if LayoutEntity(dst[0]) != src || dst[1].ID != 8 || dst[1].Name != "" {
+			t.Errorf("Expected copied entities, got %+v", dst)
+		}
+	})
+
+	t.Run("Partial layout", func(t *testing.T) {
+		partial := LayoutPartial{ID: 7, Name: "Ann", Age: 30, Active: true, Pos: LayoutPoint{1, 2}, Tags: []string{"a"}, hidden: 9}
+
+		for _, name := range []string{"value", "pointer"} {
+			var dst LayoutPartialDTO
+			var err error
+			if name == "value" {
+				dst, err = mapster.Map[LayoutPartialDTO](partial)
+			} else {
+				dst, err = mapster.Map[LayoutPartialDTO](&partial)
+			}
+			if err != nil {
+				t.Fatalf("Map failed: %v", err)
+			}
+			if dst.Age != 30 || !dst.Active || dst.Pos != (LayoutPoint{1, 2}) || dst.Name != "Ann" {
+				t.Errorf("Expected copied fields from %s, got %+v", name, dst)
+			}
+			if dst.ID != 7 || dst.Extra != 0 || dst.hidden != 0 || len(dst.Tags) != 1 {
+				t.Errorf("Expected mapped fields from %s, got %+v", name, dst)
+			}
+		}
+	})
+
+	t.Run("Ignore zero", func(t *testing.T) {
+		dst := LayoutEntityDTO{ID: 1, Name: "Bob", Age: 40}
+		if err := mapster.MapTo(LayoutEntity{ID: 2}, &dst, mapster.IgnoreZero()); err != nil {
+			t.Fatalf("MapTo failed: %v", err)
+		}
+		if dst.ID != 2 || dst.Name != "Bob" || dst.Age != 40 {
+			t.Errorf("Expected zero fields kept, got %+v", dst)
+		}
+	})
+
+	t.Run("Field mask", func(t *testing.T) {
+		dst := LayoutEntityDTO{ID: 1, Name: "Bob"}
+		if err := mapster.MapTo(src, &dst, mapster.FieldMask("Name")); err != nil {
+			t.Fatalf("MapTo failed: %v", err)
+		}
+		if dst.ID != 1 || dst.Name != "Ann" || dst.Age != 0 {
+			t.Errorf("Expected only Name mapped, got %+v", dst)
+		}
+	})
+
+	t.Run("Field tags", func(t *testing.T) {
+		dst := LayoutTagged{ID: 1, Name: "Bob"}
+		if err := mapster.MapTo(struct {
+			ID   int64
+			Name string
+		}{ID: 2}, &dst); err != nil {
+			t.Fatalf("MapTo failed: %v", err)
+		}
+		if dst.ID != 2 || dst.Name != "Bob" {
+			t.Errorf("Expected omitzero field kept, got %+v", dst)
+		}
+	})
+
+	t.Run("Member resolvers", func(t *testing.T) {
+		dst, err := mapster.Map[LayoutHookedDTO](LayoutHooked{ID: 1, Name: "Ann"})
+		if err != nil {
+			t.Fatalf("Map failed: %v", err)
+		}
+		if dst.ID != 1 || dst.Name != "resolved Ann" {
+			t.Errorf("Expected resolved Name, got %+v", dst)
+		}
+	})
+}
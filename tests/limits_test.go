@@ -0,0 +1,125 @@
+package tests
+
+import (
+	"errors"
+	"testing"
+
+	mapster "github.com/deferz/go-mapster"
+)
+
+// Types for limit tests
+type LimitNode struct {
+	Value int
+	Next  *LimitNode
+	Items []int
+}
+
+type LimitNodeDTO struct {
+	Value int
+	Next  *LimitNodeDTO
+	Items []int64
+}
+
+type LimitRequest struct {
+	Payload any
+}
+
+func newLimitChain(n int) *LimitNode {
+	var head *LimitNode
+	for i := n; i > 0; i-- {
+		head = &LimitNode{Value: i, Next: head}
+	}
+	return head
+}
+
+// TestLimits tests the depth and size limits
+func TestLimits(t *testing.T) {
+	t.Run("Unlimited by default", func(t *testing.T) {
+		if _, err := mapster.Map[*LimitNodeDTO](newLimitChain(100)); err != nil {
+			t.Fatalf("Map failed: %v", err)
+		}
+	})
+
+	t.Run("Max depth", func(t *testing.T) {
+		if _, err := mapster.Map[*LimitNodeDTO](newLimitChain(3), mapster.MaxDepth(10)); err != nil {
+			t.Fatalf("Map failed: %v", err)
+		}
+
+		_, err := mapster.Map[*LimitNodeDTO](newLimitChain(50), mapster.MaxDepth(10))
+		var limitErr *mapster.LimitError
+		if !errors.As(err, &limitErr) {
+			t.Fatalf("Expected LimitError, got %v", err)
+		}
+		if limitErr.Kind != mapster.LimitDepth || limitErr.Max != 10 {
+			t.Errorf("Expected depth limit of 10, got %+v", limitErr)
+		}
+		if limitErr.Path != "Next.Next.Next.Next.Next" {
+			t.Errorf("Expected path of the offending member, got %s", limitErr.Path)
+		}
+	})
+
+	t.Run("Max collection length", func(t *testing.T) {
+		src := &LimitNode{Items: make([]int, 20)}
+
+		_, err := mapster.Map[LimitNodeDTO](src, mapster.MaxCollectionLen(10))
+		var limitErr *mapster.LimitError
+		if !errors.As(err, &limitErr) || limitErr.Kind != mapster.LimitCollectionLen {
+			t.Fatalf("Expected collection length LimitError, got %v", err)
+		}
+		if limitErr.Path != "Items" {
+			t.Errorf("Expected path Items, got %s", limitErr.Path)
+		}
+
+		if _, err := mapster.Map[map[string]int](map[string]int{"a": 1, "b": 2}, mapster.MaxCollectionLen(1)); err == nil {
+			t.Error("Expected error for oversized map, got nil")
+		}
+	})
+
+	t.Run("Max elements", func(t *testing.T) {
+		src := [][]int{make([]int, 4), make([]int, 4), make([]int, 4)}
+
+		if _, err := mapster.Map[[][]int64](src, mapster.MaxElements(15)); err != nil {
+			t.Fatalf("Map failed: %v", err)
+		}
+
+		_, err := mapster.Map[[][]int64](src, mapster.MaxElements(10))
+		var limitErr *mapster.LimitError
+		if !errors.As(err, &limitErr) || limitErr.Kind != mapster.LimitElements {
+			t.Fatalf("Expected elements LimitError, got %v", err)
+		}
+		if limitErr.Path != "[1]" {
+			t.Errorf("Expected path [1], got %s", limitErr.Path)
+		}
+	})
+
+	t.Run("Untyped payloads", func(t *testing.T) {
+		payload := map[string]any{}
+		for i := 0; i < 200; i++ {
+			payload = map[string]any{"Child": payload}
+		}
+
+		_, err := mapster.Map[map[string]any](LimitRequest{Payload: payload}, mapster.MaxDepth(10))
+		var limitErr *mapster.LimitError
+		if !errors.As(err, &limitErr) || limitErr.Kind != mapster.LimitDepth {
+			t.Fatalf("Expected depth LimitError, got %v", err)
+		}
+
+		_, err = mapster.Map[map[string]any](LimitRequest{Payload: make([]any, 10000)}, mapster.MaxElements(10))
+		if !errors.As(err, &limitErr) || limitErr.Kind != mapster.LimitElements {
+			t.Fatalf("Expected elements LimitError, got %v", err)
+		}
+		if limitErr.Path != "[Payload]" {
+			t.Errorf("Expected path [Payload], got %s", limitErr.Path)
+		}
+
+		if _, err := mapster.Map[map[string]any](LimitRequest{Payload: []any{1, 2}}, mapster.MaxElements(10)); err != nil {
+			t.Errorf("Expected small payload within limits, got %v", err)
+		}
+	})
+
+	t.Run("Clone", func(t *testing.T) {
+		if _, err := mapster.Clone(newLimitChain(50), mapster.MaxDepth(10)); err == nil {
+			t.Fatal("Expected depth limit error, got nil")
+		}
+	})
+}
@@ -0,0 +1,134 @@
+package tests
+
+import (
+	"testing"
+
+	mapster "github.com/deferz/go-mapster"
+)
+
+// Types for map merge tests
+type Setting struct {
+	Value   string
+	Default string
+}
+
+type SettingPatch struct {
+	Value string
+}
+
+type Profile struct {
+	Labels   map[string]string
+	Settings map[string]Setting `mapster:",deepmerge,prune"`
+}
+
+type ProfilePatch struct {
+	Labels   map[string]string
+	Settings map[string]SettingPatch
+}
+
+// TestMapMerge tests updating existing target maps in place
+func TestMapMerge(t *testing.T) {
+	t.Run("Replace by default", func(t *testing.T) {
+		dst := map[string]int{"a": 1, "b": 2}
+		if err := mapster.MapTo(map[string]int{"b": 3}, &dst); err != nil {
+			t.Fatalf("MapTo failed: %v", err)
+		}
+		if len(dst) != 1 || dst["b"] != 3 {
+			t.Errorf("Expected replaced map, got %v", dst)
+		}
+	})
+
+	t.Run("Merge keeps existing keys", func(t *testing.T) {
+		dst := map[string]int{"a": 1, "b": 2}
+		original := dst
+		if err := mapster.MapTo(map[string]int{"b": 3, "c": 4}, &dst, mapster.MergeMaps(false)); err != nil {
+			t.Fatalf("MapTo failed: %v", err)
+		}
+		if len(dst) != 3 || dst["a"] != 1 || dst["b"] != 3 || dst["c"] != 4 {
+			t.Errorf("Expected merged map, got %v", dst)
+		}
+		if original["c"] != 4 {
+			t.Error("Expected the existing map to be updated in place")
+		}
+	})
+
+	t.Run("Merge removes missing keys", func(t *testing.T) {
+		dst := map[string]int{"a": 1, "b": 2}
+		if err := mapster.MapTo(map[string]int64{"b": 3}, &dst, mapster.MergeMaps(true)); err != nil {
+			t.Fatalf("MapTo failed: %v", err)
+		}
+		if len(dst) != 1 || dst["b"] != 3 {
+			t.Errorf("Expected only key b, got %v", dst)
+		}
+	})
+
+	t.Run("Merge overwrites struct values", func(t *testing.T) {
+		dst := map[string]Setting{"mode": {Value: "fast", Default: "safe"}}
+		src := map[string]SettingPatch{"mode": {Value: "slow"}}
+		if err := mapster.MapTo(src, &dst, mapster.MergeMaps(false)); err != nil {
+			t.Fatalf("MapTo failed: %v", err)
+		}
+		if dst["mode"].Value != "slow" || dst["mode"].Default != "" {
+			t.Errorf("Expected overwritten entry, got %+v", dst["mode"])
+		}
+	})
+
+	t.Run("Deep merge struct values", func(t *testing.T) {
+		dst := map[string]Setting{"mode": {Value: "fast", Default: "safe"}}
+		src := map[string]SettingPatch{"mode": {Value: "slow"}, "level": {Value: "2"}}
+		if err := mapster.MapTo(src, &dst, mapster.DeepMergeMaps(false)); err != nil {
+			t.Fatalf("MapTo failed: %v", err)
+		}
+		if dst["mode"].Value != "slow" || dst["mode"].Default != "safe" {
+			t.Errorf("Expected merged entry, got %+v", dst["mode"])
+		}
+		if dst["level"].Value != "2" {
+			t.Errorf("Expected new entry, got %+v", dst["level"])
+		}
+	})
+
+	t.Run("Deep merge pointers and nested maps", func(t *testing.T) {
+		entry := &Setting{Value: "a", Default: "x"}
+		dst := map[string]map[string]*Setting{"group": {"one": entry, "two": {Value: "b"}}}
+		src := map[string]map[string]*SettingPatch{"group": {"one": {Value: "c"}}}
+		if err := mapster.MapTo(src, &dst, mapster.DeepMergeMaps(false)); err != nil {
+			t.Fatalf("MapTo failed: %v", err)
+		}
+		if dst["group"]["one"] != entry || entry.Value != "c" || entry.Default != "x" {
+			t.Errorf("Expected entry updated in place, got %+v", dst["group"]["one"])
+		}
+		if dst["group"]["two"] == nil {
+			t.Error("Expected nested key two to be kept")
+		}
+	})
+
+	t.Run("Field tag", func(t *testing.T) {
+		dst := Profile{
+			Labels:   map[string]string{"env": "dev"},
+			Settings: map[string]Setting{"mode": {Value: "fast", Default: "safe"}, "old": {Value: "x"}},
+		}
+		src := ProfilePatch{
+			Labels:   map[string]string{"team": "core"},
+			Settings: map[string]SettingPatch{"mode": {Value: "slow"}},
+		}
+		if err := mapster.MapTo(src, &dst); err != nil {
+			t.Fatalf("MapTo failed: %v", err)
+		}
+		if len(dst.Labels) != 1 || dst.Labels["team"] != "core" {
+			t.Errorf("Expected untagged map replaced, got %v", dst.Labels)
+		}
+		if len(dst.Settings) != 1 || dst.Settings["mode"].Default != "safe" || dst.Settings["mode"].Value != "slow" {
+			t.Errorf("Expected deep merged and pruned settings, got %+v", dst.Settings)
+		}
+	})
+
+	t.Run("Nil target map", func(t *testing.T) {
+		var dst map[string]int
+		if err := mapster.MapTo(map[string]int{"a": 1}, &dst, mapster.MergeMaps(true)); err != nil {
+			t.Fatalf("MapTo failed: %v", err)
+		}
+		if dst["a"] != 1 {
+			t.Errorf("Expected new map, got %v", dst)
+		}
+	})
+}
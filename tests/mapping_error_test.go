@@ -0,0 +1,158 @@
+package tests
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	mapster "github.com/deferz/go-mapster"
+)
+
+// Types for mapping error tests
+type ErrLine struct {
+	Price any
+}
+
+type ErrLineDTO struct {
+	Price float64
+}
+
+type ErrOrder struct {
+	Items []ErrLine
+}
+
+type ErrOrderDTO struct {
+	Items []ErrLineDTO
+}
+
+type ErrCustomer struct {
+	Orders []ErrOrder
+}
+
+type ErrCustomerDTO struct {
+	Orders []ErrOrderDTO
+}
+
+type ErrCatalog struct {
+	Items []LineItem
+}
+
+type ErrCatalogIndex struct {
+	Items map[string]LineItem
+}
+
+type ErrCatalogState struct {
+	Items []LineItemState
+}
+
+// TestMappingError tests structured mapping errors
+func TestMappingError(t *testing.T) {
+	src := ErrCustomer{Orders: []ErrOrder{
+		{Items: []ErrLine{{Price: 1.5}}},
+		{Items: []ErrLine{{Price: "2"}, {Price: 3}}},
+	}}
+
+	t.Run("Map", func(t *testing.T) {
+		_, err := mapster.Map[ErrCustomerDTO](src)
+		if err == nil {
+			t.Fatal("Expected error, got nil")
+		}
+		if !strings.HasPrefix(err.Error(), "mapping failed: ") {
+			t.Errorf("Expected mapping failed prefix, got %v", err)
+		}
+
+		var mappingErr *mapster.MappingError
+		if !errors.As(err, &mappingErr) {
+			t.Fatalf("Expected MappingError, got %v", err)
+		}
+		if mappingErr.DstPath != "Orders[1].Items[0].Price" || mappingErr.SrcPath != mappingErr.DstPath {
+			t.Errorf("Expected path Orders[1].Items[0].Price, got %s from %s", mappingErr.DstPath, mappingErr.SrcPath)
+		}
+		if mappingErr.SrcType != reflect.TypeOf("") || mappingErr.DstType != reflect.TypeOf(float64(0)) {
+			t.Errorf("Expected string to float64, got %v to %v", mappingErr.SrcType, mappingErr.DstType)
+		}
+		if mappingErr.Cause == nil || !strings.Contains(mappingErr.Cause.Error(), "cannot convert") {
+			t.Errorf("Expected conversion cause, got %v", mappingErr.Cause)
+		}
+	})
+
+	t.Run("MapTo", func(t *testing.T) {
+		var dst ErrCustomerDTO
+		err := mapster.MapTo(src, &dst)
+
+		var mappingErr *mapster.MappingError
+		if !errors.As(err, &mappingErr) {
+			t.Fatalf("Expected MappingError, got %v", err)
+		}
+		if !strings.Contains(err.Error(), "Orders[1].Items[0].Price") {
+			t.Errorf("Expected path in message, got %v", err)
+		}
+	})
+
+	t.Run("Source path from map", func(t *testing.T) {
+		_, err := mapster.Map[ErrLineDTO](map[string]any{"Price": []int{1}})
+
+		var mappingErr *mapster.MappingError
+		if !errors.As(err, &mappingErr) {
+			t.Fatalf("Expected MappingError, got %v", err)
+		}
+		if mappingErr.DstPath != "Price" || mappingErr.SrcPath != "[Price]" {
+			t.Errorf("Expected Price from [Price], got %s from %s", mappingErr.DstPath, mappingErr.SrcPath)
+		}
+	})
+
+	t.Run("Root value", func(t *testing.T) {
+		_, err := mapster.Map[float64]("x")
+
+		var mappingErr *mapster.MappingError
+		if !errors.As(err, &mappingErr) {
+			t.Fatalf("Expected MappingError, got %v", err)
+		}
+		if mappingErr.DstPath != "" {
+			t.Errorf("Expected empty root path, got %s", mappingErr.DstPath)
+		}
+	})
+
+	t.Run("Wraps limit errors", func(t *testing.T) {
+		_, err := mapster.Map[ErrCustomerDTO](src, mapster.MaxCollectionLen(1))
+
+		var mappingErr *mapster.MappingError
+		var limitErr *mapster.LimitError
+		if !errors.As(err, &mappingErr) || !errors.As(err, &limitErr) {
+			t.Fatalf("Expected MappingError wrapping LimitError, got %v", err)
+		}
+		if mappingErr.DstPath != "Orders" || limitErr.Path != "Orders" {
+			t.Errorf("Expected path Orders, got %s and %s", mappingErr.DstPath, limitErr.Path)
+		}
+	})
+
+	t.Run("Key failures at the element index", func(t *testing.T) {
+		catalog := ErrCatalog{Items: []LineItem{{SKU: "a"}, {SKU: "b"}, {SKU: "a"}}}
+		state := ErrCatalogState{Items: []LineItemState{{SKU: "a"}, {SKU: "b"}}}
+
+		_, duplicateErr := mapster.Map[ErrCatalogIndex](catalog, mapster.KeyBy("SKU"))
+		_, selectorErr := mapster.Map[ErrCatalogIndex](catalog, mapster.KeyBy("Missing"))
+		syncErr := mapster.MapTo(catalog, &state, mapster.SyncCollectionsByKey("Missing", false))
+
+		cases := []struct {
+			name string
+			err  error
+			path string
+		}{
+			{"duplicate key", duplicateErr, "Items[2]"},
+			{"key selector", selectorErr, "Items[0]"},
+			{"sync key", syncErr, "Items[0]"},
+		}
+		for _, c := range cases {
+			var mappingErr *mapster.MappingError
+			if !errors.As(c.err, &mappingErr) {
+				t.Errorf("Expected MappingError for %s, got %v", c.name, c.err)
+				continue
+			}
+			if mappingErr.DstPath != c.path || mappingErr.SrcPath != c.path {
+				t.Errorf("Expected %s failure at %s, got %s from %s", c.name, c.path, mappingErr.DstPath, mappingErr.SrcPath)
+			}
+		}
+	})
+}
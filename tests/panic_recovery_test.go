@@ -0,0 +1,104 @@
+package tests
+
+import (
+	"errors"
+	"runtime"
+	"testing"
+
+	mapster "github.com/deferz/go-mapster"
+)
+
+// Types for panic recovery tests
+type PanicItem struct {
+	ID int
+}
+
+type PanicHolder struct {
+	Name  string
+	Items []PanicItem
+}
+
+type PanicHolderDTO struct {
+	Name  string
+	Items map[any]PanicItem
+}
+
+// TestPanicRecovery tests that panics raised while mapping are returned as errors
+func TestPanicRecovery(t *testing.T) {
+	src := PanicHolder{Name: "holder", Items: []PanicItem{{ID: 1}, {ID: 2}}}
+
+	t.Run("Panic in key selector", func(t *testing.T) {
+		keyBy := mapster.KeyByFunc(func(elem any) (any, error) {
+			panic("boom")
+		})
+
+		_, err := mapster.Map[PanicHolderDTO](src, keyBy)
+		if err == nil {
+			t.Fatal("Expected error, got nil")
+		}
+
+		var mappingErr *mapster.MappingError
+		if !errors.As(err, &mappingErr) {
+			t.Fatalf("Expected MappingError, got %v", err)
+		}
+		if mappingErr.DstPath != "Items" {
+			t.Errorf("Expected path Items, got %s", mappingErr.DstPath)
+		}
+
+		var panicErr *mapster.PanicError
+		if !errors.As(err, &panicErr) {
+			t.Fatalf("Expected PanicError, got %v", err)
+		}
+		if panicErr.Value != "boom" {
+			t.Errorf("Expected panic value boom, got %v", panicErr.Value)
+		}
+		if len(panicErr.Stack) == 0 {
+			t.Error("Expected stack trace, got none")
+		}
+	})
+
+	t.Run("Runtime panic", func(t *testing.T) {
+		// Slices are not hashable, so storing them as map keys panics inside reflect
+		keyBy := mapster.KeyByFunc(func(elem any) (any, error) {
+			return []int{elem.(PanicItem).ID}, nil
+		})
+
+		var dst PanicHolderDTO
+		err := mapster.MapTo(src, &dst, keyBy)
+
+		var runtimeErr runtime.Error
+		if !errors.As(err, &runtimeErr) {
+			t.Fatalf("Expected runtime error, got %v", err)
+		}
+		if dst.Name != "holder" {
+			t.Errorf("Expected Name holder, got %s", dst.Name)
+		}
+	})
+
+	t.Run("Propagated panics", func(t *testing.T) {
+		keyBy := mapster.KeyByFunc(func(elem any) (any, error) {
+			panic("boom")
+		})
+
+		defer func() {
+			if r := recover(); r != "boom" {
+				t.Errorf("Expected panic boom, got %v", r)
+			}
+		}()
+		_, _ = mapster.Map[PanicHolderDTO](src, keyBy, mapster.PropagatePanics())
+		t.Error("Expected panic, got none")
+	})
+
+	t.Run("Not collected", func(t *testing.T) {
+		keyBy := mapster.KeyByFunc(func(elem any) (any, error) {
+			panic("boom")
+		})
+
+		_, err := mapster.Map[PanicHolderDTO](src, keyBy, mapster.CollectErrors())
+
+		var errs mapster.MappingErrors
+		if errors.As(err, &errs) {
+			t.Errorf("Expected panic to stop the mapping, got %v", errs)
+		}
+	})
+}
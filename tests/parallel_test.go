@@ -0,0 +1,174 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	mapster "github.com/deferz/go-mapster"
+)
+
+// Types for parallel mapping tests
+type ParallelRow struct {
+	ID    int
+	Name  string
+	Score any
+	Tags  []string
+}
+
+type ParallelRowDTO struct {
+	ID    int64
+	Name  string
+	Score float64
+	Tags  []string
+}
+
+func newParallelRows(n int) []ParallelRow {
+	rows := make([]ParallelRow, n)
+	for i := range rows {
+		rows[i] = ParallelRow{ID: i, Name: fmt.Sprintf("row-%d", i), Score: float64(i) / 2, Tags: []string{"a", "b"}}
+	}
+	return rows
+}
+
+// TestParallel tests mapping large collections concurrently
+func TestParallel(t *testing.T) {
+	t.Run("Preserves order", func(t *testing.T) {
+		rows := newParallelRows(10000)
+
+		dst, err := mapster.Map[[]ParallelRowDTO](rows, mapster.Parallel(100, 4))
+		if err != nil {
+			t.Fatalf("Map failed: %v", err)
+		}
+		if len(dst) != len(rows) {
+			t.Fatalf("Expected %d rows, got %d", len(rows), len(dst))
+		}
+		for i, row := range dst {
+			if row.ID != int64(i) || row.Name != rows[i].Name || row.Score != float64(i)/2 || len(row.Tags) != 2 {
+				t.Fatalf("Expected row %d to match source, got %+v", i, row)
+			}
+		}
+	})
+
+	t.Run("Default workers", func(t *testing.T) {
+		rows := newParallelRows(3000)
+
+		dst, err := mapster.Map[[]ParallelRowDTO](rows, mapster.Parallel(0, 0))
+		if err != nil {
+			t.Fatalf("Map failed: %v", err)
+		}
+		if dst[2999].ID != 2999 {
+			t.Errorf("Expected last ID 2999, got %d", dst[2999].ID)
+		}
+	})
+
+	t.Run("Array", func(t *testing.T) {
+		var src [500]int32
+		for i := range src {
+			src[i] = int32(i)
+		}
+
+		dst, err := mapster.Map[[500]int64](src, mapster.Parallel(10, 8))
+		if err != nil {
+			t.Fatalf("Map failed: %v", err)
+		}
+		for i, v := range dst {
+			if v != int64(i) {
+				t.Fatalf("Expected %d at index %d, got %d", i, i, v)
+			}
+		}
+	})
+
+	t.Run("First error", func(t *testing.T) {
+		rows := newParallelRows(1000)
+		rows[300].Score = "bad"
+		rows[800].Score = "bad"
+
+		for run := 0; run < 20; run++ {
+			_, err := mapster.Map[[]ParallelRowDTO](rows, mapster.Parallel(10, 8))
+
+			var mappingErr *mapster.MappingError
+			if !errors.As(err, &mappingErr) {
+				t.Fatalf("Expected MappingError, got %v", err)
+			}
+			if mappingErr.DstPath != "[300].Score" {
+				t.Fatalf("Expected first failure at [300].Score, got %s", mappingErr.DstPath)
+			}
+		}
+	})
+
+	t.Run("Collected errors", func(t *testing.T) {
+		rows := newParallelRows(1000)
+		rows[999].Score = "bad"
+		rows[5].Score = "bad"
+		rows[500].Score = "bad"
+
+		dst, err := mapster.Map[[]ParallelRowDTO](rows, mapster.Parallel(10, 8), mapster.CollectErrors())
+
+		var errs mapster.MappingErrors
+		if !errors.As(err, &errs) {
+			t.Fatalf("Expected MappingErrors, got %v", err)
+		}
+		expected := []string{"[5].Score", "[500].Score", "[999].Score"}
+		if len(errs) != len(expected) {
+			t.Fatalf("Expected %d errors, got %d: %v", len(expected), len(errs), errs)
+		}
+		for i, path := range expected {
+			if errs[i].DstPath != path {
+				t.Errorf("Expected error %d at %s, got %s", i, path, errs[i].DstPath)
+			}
+		}
+		if dst[6].ID != 6 {
+			t.Errorf("Expected other rows to be mapped, got %+v", dst[6])
+		}
+	})
+
+	t.Run("Cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, err := mapster.MapCtx[[]ParallelRowDTO](ctx, newParallelRows(1000), mapster.Parallel(10, 4))
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("Expected context.Canceled, got %v", err)
+		}
+	})
+
+	t.Run("Nested cycles", func(t *testing.T) {
+		trees := make([]*TreeNode, 200)
+		for i := range trees {
+			trees[i] = newTree()
+		}
+
+		_, err := mapster.Map[[]*TreeNodeDTO](trees, mapster.Parallel(10, 4))
+		if !errors.Is(err, mapster.ErrCycle) {
+			t.Errorf("Expected ErrCycle, got %v", err)
+		}
+
+		dst, err := mapster.Map[[]*TreeNodeDTO](trees, mapster.Parallel(10, 4), mapster.OnCycle(mapster.CycleBreak))
+		if err != nil {
+			t.Fatalf("Map failed: %v", err)
+		}
+		if dst[150].Children[0].Name != "child" {
+			t.Errorf("Expected child, got %s", dst[150].Children[0].Name)
+		}
+	})
+
+	t.Run("Propagated panics", func(t *testing.T) {
+		keyBy := mapster.KeyByFunc(func(elem any) (any, error) {
+			panic("boom")
+		})
+		src := make([][]PanicItem, 100)
+		for i := range src {
+			src[i] = []PanicItem{{ID: i}}
+		}
+
+		defer func() {
+			if r := recover(); r != "boom" {
+				t.Errorf("Expected panic boom, got %v", r)
+			}
+		}()
+		_, _ = mapster.Map[[]map[any]PanicItem](src, keyBy, mapster.Parallel(10, 4), mapster.PropagatePanics())
+		t.Error("Expected panic, got none")
+	})
+}
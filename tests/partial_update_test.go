@@ -0,0 +1,170 @@
+package tests
+
+import (
+	"testing"
+
+	mapster "github.com/deferz/go-mapster"
+)
+
+// Types for partial update tests
+type AccountAddress struct {
+	Street string
+	City   string
+}
+
+type Account struct {
+	Name    string
+	Age     int
+	Email   *string
+	Tags    []string
+	Address AccountAddress
+	Billing *AccountAddress
+}
+
+type AccountPatch struct {
+	Name    *string
+	Age     *int
+	Email   *string
+	Tags    []string
+	Address AccountAddress
+	Billing *AccountAddress
+}
+
+type AccountForm struct {
+	Name string `mapster:",omitzero"`
+	Age  int
+}
+
+type AccountFormInput struct {
+	Name string
+	Age  int
+}
+
+type AccountPairPatch struct {
+	Name *string
+	Age  *int
+}
+
+func init() {
+	mapster.NewMapperConfig[AccountPairPatch, Account]().With(mapster.IgnoreNil()).Register()
+}
+
+func newAccount() Account {
+	email := "ann@example.com"
+	return Account{
+		Name:    "Ann",
+		Age:     30,
+		Email:   &email,
+		Tags:    []string{"admin"},
+		Address: AccountAddress{Street: "Main St", City: "Berlin"},
+		Billing: &AccountAddress{Street: "Side St", City: "Paris"},
+	}
+}
+
+// TestPartialUpdates tests skipping nil and zero source values
+func TestPartialUpdates(t *testing.T) {
+	t.Run("Nil values overwrite by default", func(t *testing.T) {
+		dst := newAccount()
+		if err := mapster.MapTo(AccountPatch{}, &dst); err != nil {
+			t.Fatalf("MapTo failed: %v", err)
+		}
+		if dst.Email != nil || dst.Tags != nil {
+			t.Errorf("Expected nil values copied, got %+v", dst)
+		}
+	})
+
+	t.Run("Ignore nil", func(t *testing.T) {
+		dst := newAccount()
+		billing := dst.Billing
+		age := 31
+		patch := AccountPatch{Age: &age, Billing: &AccountAddress{City: "Rome"}}
+
+		if err := mapster.MapTo(patch, &dst, mapster.IgnoreNil()); err != nil {
+			t.Fatalf("MapTo failed: %v", err)
+		}
+		if dst.Name != "Ann" || dst.Age != 31 || dst.Email == nil || len(dst.Tags) != 1 {
+			t.Errorf("Expected only Age updated, got %+v", dst)
+		}
+		if dst.Billing != billing || billing.City != "Rome" || billing.Street != "" {
+			t.Errorf("Expected existing billing updated in place, got %+v", dst.Billing)
+		}
+		if dst.Address.City != "" {
+			t.Errorf("Expected zero value copied without IgnoreZero, got %+v", dst.Address)
+		}
+	})
+
+	t.Run("Ignore zero merges nested structs", func(t *testing.T) {
+		dst := newAccount()
+		patch := AccountPatch{Address: AccountAddress{City: "Madrid"}, Billing: &AccountAddress{Street: "New St"}}
+
+		if err := mapster.MapTo(patch, &dst, mapster.IgnoreZero()); err != nil {
+			t.Fatalf("MapTo failed: %v", err)
+		}
+		if dst.Address.Street != "Main St" || dst.Address.City != "Madrid" {
+			t.Errorf("Expected merged address, got %+v", dst.Address)
+		}
+		if dst.Billing.Street != "New St" || dst.Billing.City != "Paris" {
+			t.Errorf("Expected merged billing, got %+v", dst.Billing)
+		}
+		if dst.Name != "Ann" || dst.Email == nil {
+			t.Errorf("Expected untouched fields kept, got %+v", dst)
+		}
+	})
+
+	t.Run("Identical types", func(t *testing.T) {
+		dst := newAccount()
+		if err := mapster.MapTo(Account{Age: 40}, &dst, mapster.IgnoreZero()); err != nil {
+			t.Fatalf("MapTo failed: %v", err)
+		}
+		if dst.Name != "Ann" || dst.Age != 40 || dst.Address.City != "Berlin" {
+			t.Errorf("Expected merged account, got %+v", dst)
+		}
+	})
+
+	t.Run("Field tag", func(t *testing.T) {
+		dst := AccountForm{Name: "Ann", Age: 30}
+		if err := mapster.MapTo(AccountFormInput{}, &dst); err != nil {
+			t.Fatalf("MapTo failed: %v", err)
+		}
+		if dst.Name != "Ann" || dst.Age != 0 {
+			t.Errorf("Expected Name kept and Age cleared, got %+v", dst)
+		}
+	})
+
+	t.Run("Pair", func(t *testing.T) {
+		dst := newAccount()
+		name := "Bob"
+		if err := mapster.MapTo(AccountPairPatch{Name: &name}, &dst); err != nil {
+			t.Fatalf("MapTo failed: %v", err)
+		}
+		if dst.Name != "Bob" || dst.Age != 30 {
+			t.Errorf("Expected only Name updated, got %+v", dst)
+		}
+	})
+
+	t.Run("Defaults", func(t *testing.T) {
+		mapster.SetDefaults(mapster.IgnoreNil())
+		defer mapster.SetDefaults()
+
+		dst := newAccount()
+		if err := mapster.MapTo(AccountPatch{}, &dst); err != nil {
+			t.Fatalf("MapTo failed: %v", err)
+		}
+		if dst.Email == nil || len(dst.Tags) != 1 {
+			t.Errorf("Expected nil values skipped, got %+v", dst)
+		}
+	})
+
+	t.Run("Merge map ignores nil entries", func(t *testing.T) {
+		first := &AccountAddress{City: "Oslo"}
+		dst := map[string]*AccountAddress{"home": first}
+		src := map[string]*AccountAddress{"home": nil, "work": {City: "Bergen"}}
+
+		if err := mapster.MapTo(src, &dst, mapster.MergeMaps(false), mapster.IgnoreNil()); err != nil {
+			t.Fatalf("MapTo failed: %v", err)
+		}
+		if dst["home"] != first || dst["work"].City != "Bergen" {
+			t.Errorf("Expected home kept and work added, got %+v", dst)
+		}
+	})
+}
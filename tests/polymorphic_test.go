@@ -0,0 +1,115 @@
+package tests
+
+import (
+	"testing"
+
+	mapster "github.com/deferz/go-mapster"
+)
+
+// Types for polymorphic mapping tests
+type Shape interface {
+	Area() float64
+}
+
+type Circle struct {
+	Radius float64
+}
+
+func (c Circle) Area() float64 { return 3 * c.Radius * c.Radius }
+
+type Square struct {
+	Side float64
+}
+
+func (s *Square) Area() float64 { return s.Side * s.Side }
+
+type Triangle struct {
+	Base   float64
+	Height float64
+}
+
+func (t Triangle) Area() float64 { return t.Base * t.Height / 2 }
+
+type ShapeDTO interface {
+	Kind() string
+}
+
+type CircleDTO struct {
+	Radius float64
+}
+
+func (CircleDTO) Kind() string { return "circle" }
+
+type SquareDTO struct {
+	Side float64
+}
+
+func (*SquareDTO) Kind() string { return "square" }
+
+func init() {
+	mapster.NewMapperConfig[Shape, ShapeDTO]().
+		Include(mapster.NewMapperConfig[Circle, CircleDTO]()).
+		Include(mapster.NewMapperConfig[*Square, *SquareDTO]()).
+		Register()
+}
+
+// TestPolymorphicMapping tests derived-type dispatch for interface pairs
+func TestPolymorphicMapping(t *testing.T) {
+	t.Run("Slice of interfaces", func(t *testing.T) {
+		src := []Shape{Circle{Radius: 2}, &Square{Side: 3}}
+
+		dst, err := mapster.Map[[]ShapeDTO](src)
+		if err != nil {
+			t.Fatalf("Map failed: %v", err)
+		}
+
+		if len(dst) != len(src) {
+			t.Fatalf("Expected slice length %d, got %d", len(src), len(dst))
+		}
+		if circle, ok := dst[0].(CircleDTO); !ok || circle.Radius != 2 {
+			t.Errorf("Expected CircleDTO{Radius: 2} at index 0, got %#v", dst[0])
+		}
+		if square, ok := dst[1].(*SquareDTO); !ok || square.Side != 3 {
+			t.Errorf("Expected &SquareDTO{Side: 3} at index 1, got %#v", dst[1])
+		}
+	})
+
+	t.Run("Interface field", func(t *testing.T) {
+		type Drawing struct {
+			Name  string
+			Shape Shape
+		}
+		type DrawingDTO struct {
+			Name  string
+			Shape ShapeDTO
+		}
+
+		dst, err := mapster.Map[DrawingDTO](Drawing{Name: "logo", Shape: Circle{Radius: 1}})
+		if err != nil {
+			t.Fatalf("Map failed: %v", err)
+		}
+
+		if circle, ok := dst.Shape.(CircleDTO); !ok || circle.Radius != 1 {
+			t.Errorf("Expected CircleDTO{Radius: 1}, got %#v", dst.Shape)
+		}
+	})
+
+	t.Run("Concrete source", func(t *testing.T) {
+		dst, err := mapster.Map[ShapeDTO](&Square{Side: 4})
+		if err != nil {
+			t.Fatalf("Map failed: %v", err)
+		}
+
+		if dst.Kind() != "square" {
+			t.Errorf("Expected square, got %s", dst.Kind())
+		}
+	})
+
+	t.Run("No derived mapping", func(t *testing.T) {
+		src := []Shape{Circle{Radius: 1}, Triangle{Base: 1, Height: 2}}
+
+		if _, err := mapster.Map[[]ShapeDTO](src); err == nil {
+			t.Fatal("Expected error for unregistered derived type, got nil")
+		}
+	})
+}
@@ -0,0 +1,92 @@
+package tests
+
+import (
+	"testing"
+
+	mapster "github.com/deferz/go-mapster"
+)
+
+// Types for reference preservation tests
+type RefCustomer struct {
+	Name string
+}
+
+type RefCustomerDTO struct {
+	Name string
+}
+
+type RefInvoice struct {
+	Buyer  *RefCustomer
+	Payer  *RefCustomer
+	Others []*RefCustomer
+}
+
+type RefInvoiceDTO struct {
+	Buyer  *RefCustomerDTO
+	Payer  *RefCustomerDTO
+	Others []*RefCustomerDTO
+}
+
+// TestPreserveReferences tests reproducing shared references in the target graph
+func TestPreserveReferences(t *testing.T) {
+	customer := &RefCustomer{Name: "Ann"}
+	other := &RefCustomer{Name: "Bob"}
+	src := RefInvoice{Buyer: customer, Payer: customer, Others: []*RefCustomer{other, customer}}
+
+	t.Run("Separate copies by default", func(t *testing.T) {
+		dst, err := mapster.Map[RefInvoiceDTO](src)
+		if err != nil {
+			t.Fatalf("Map failed: %v", err)
+		}
+		if dst.Buyer == dst.Payer {
+			t.Error("Expected separate copies without PreserveReferences")
+		}
+	})
+
+	t.Run("Shared references", func(t *testing.T) {
+		dst, err := mapster.Map[RefInvoiceDTO](src, mapster.PreserveReferences())
+		if err != nil {
+			t.Fatalf("Map failed: %v", err)
+		}
+		if dst.Buyer != dst.Payer || dst.Others[1] != dst.Buyer {
+			t.Errorf("Expected all references to Ann to share one target, got %p %p %p", dst.Buyer, dst.Payer, dst.Others[1])
+		}
+		if dst.Others[0] == dst.Buyer || dst.Others[0].Name != "Bob" {
+			t.Errorf("Expected Bob mapped separately, got %+v", dst.Others[0])
+		}
+	})
+
+	t.Run("Cyclic references", func(t *testing.T) {
+		dst, err := mapster.Map[*TreeNodeDTO](newTree(), mapster.PreserveReferences())
+		if err != nil {
+			t.Fatalf("Map failed: %v", err)
+		}
+		if dst.Children[0].Parent != dst {
+			t.Error("Expected back-link to the mapped root")
+		}
+	})
+
+	t.Run("Clone", func(t *testing.T) {
+		dst, err := mapster.Clone(src, mapster.PreserveReferences())
+		if err != nil {
+			t.Fatalf("Clone failed: %v", err)
+		}
+		if dst.Buyer == customer || dst.Buyer != dst.Payer || dst.Others[1] != dst.Buyer {
+			t.Error("Expected one copy shared by all references to Ann")
+		}
+	})
+
+	t.Run("Scoped to the call", func(t *testing.T) {
+		first, err := mapster.Map[RefInvoiceDTO](src, mapster.PreserveReferences())
+		if err != nil {
+			t.Fatalf("Map failed: %v", err)
+		}
+		second, err := mapster.Map[RefInvoiceDTO](src, mapster.PreserveReferences())
+		if err != nil {
+			t.Fatalf("Map failed: %v", err)
+		}
+		if first.Buyer == second.Buyer {
+			t.Error("Expected separate targets across calls")
+		}
+	})
+}
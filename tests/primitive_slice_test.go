@@ -0,0 +1,211 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+
+	mapster "github.com/deferz/go-mapster"
+)
+
+// Types for primitive slice tests
+type PrimitiveInt int
+type PrimitiveBool bool
+type PrimitiveString string
+type PrimitiveInts []int
+
+type PrimitiveCents int64
+type PrimitiveDollars float64
+
+type PrimitivePoint struct {
+	X, Y int32
+}
+
+type PrimitivePoints []PrimitivePoint
+
+func init() {
+	mapster.NewMapperConfig[PrimitiveCents, PrimitiveDollars]().
+		ConvertUsing(func(ctx context.Context, src PrimitiveCents) (PrimitiveDollars, error) {
+			return PrimitiveDollars(src) / 100, nil
+		}).
+		Register()
+}
+
+// expectConverted checks that each target element equals the Go conversion of the source element
+func expectConverted(t *testing.T, src, dst any) {
+	t.Helper()
+
+	srcValue, dstValue := reflect.ValueOf(src), reflect.ValueOf(dst)
+	if dstValue.Kind() == reflect.Slice && dstValue.Len() != srcValue.Len() {
+		t.Fatalf("Expected %d elements, got %d", srcValue.Len(), dstValue.Len())
+	}
+	for i := 0; i < dstValue.Len() && i < srcValue.Len(); i++ {
+		expected := srcValue.Index(i).Convert(dstValue.Type().Elem()).Interface()
+		if got := dstValue.Index(i).Interface(); got != expected {
+			t.Errorf("Expected element %d to be %v, got %v", i, expected, got)
+		}
+	}
+}
+
+// TestPrimitiveSlices tests mapping slices and arrays of primitive elements
+func TestPrimitiveSlices(t *testing.T) {
+	t.Run("Widening", func(t *testing.T) {
+		ints := []int32{math.MinInt32, -1, 0, 1, math.MaxInt32}
+		wide, err := mapster.Map[[]int64](ints)
+		if err != nil {
+			t.Fatalf("Map failed: %v", err)
+		}
+		expectConverted(t, ints, wide)
+
+		floats := []float32{-1.5, 0, 3.25, float32(math.Inf(1))}
+		doubles, err := mapster.Map[[]float64](floats)
+		if err != nil {
+			t.Fatalf("Map failed: %v", err)
+		}
+		expectConverted(t, floats, doubles)
+	})
+
+	t.Run("Narrowing", func(t *testing.T) {
+		ints := []int{-129, -1, 255, 256, 1 << 40}
+		bytes, err := mapster.Map[[]uint8](ints)
+		if err != nil {
+			t.Fatalf("Map failed: %v", err)
+		}
+		expectConverted(t, ints, bytes)
+
+		floats := []float64{-2.75, 0.5, 127.9, 1e10, 1.0000001}
+		small, err := mapster.Map[[]int8](floats)
+		if err != nil {
+			t.Fatalf("Map failed: %v", err)
+		}
+		expectConverted(t, floats, small)
+
+		unsigned, err := mapster.Map[[]uint16](floats)
+		if err != nil {
+			t.Fatalf("Map failed: %v", err)
+		}
+		expectConverted(t, floats, unsigned)
+
+		singles, err := mapster.Map[[]float32](floats)
+		if err != nil {
+			t.Fatalf("Map failed: %v", err)
+		}
+		expectConverted(t, floats, singles)
+	})
+
+	t.Run("Integers to floats", func(t *testing.T) {
+		ints := []int64{math.MinInt64, -3, 1<<53 + 1, math.MaxInt64}
+		floats, err := mapster.Map[[]float32](ints)
+		if err != nil {
+			t.Fatalf("Map failed: %v", err)
+		}
+		expectConverted(t, ints, floats)
+
+		uints := []uint64{0, math.MaxUint64}
+		doubles, err := mapster.Map[[]float64](uints)
+		if err != nil {
+			t.Fatalf("Map failed: %v", err)
+		}
+		expectConverted(t, uints, doubles)
+	})
+
+	t.Run("Named types", func(t *testing.T) {
+		ints, err := mapster.Map[[]PrimitiveInt]([]int{1, 2, 3})
+		if err != nil {
+			t.Fatalf("Map failed: %v", err)
+		}
+		expectConverted(t, []int{1, 2, 3}, ints)
+
+		bools, err := mapster.Map[[]PrimitiveBool]([]bool{true, false})
+		if err != nil {
+			t.Fatalf("Map failed: %v", err)
+		}
+		expectConverted(t, []bool{true, false}, bools)
+
+		strs, err := mapster.MapSlice[string, PrimitiveString]([]string{"a", "", "c"})
+		if err != nil {
+			t.Fatalf("MapSlice failed: %v", err)
+		}
+		expectConverted(t, []string{"a", "", "c"}, strs)
+	})
+
+	t.Run("Arrays", func(t *testing.T) {
+		arr := [4]int16{-1, 2, -3, 4}
+		slice, err := mapster.Map[[]int32](arr)
+		if err != nil {
+			t.Fatalf("Map failed: %v", err)
+		}
+		expectConverted(t, arr, slice)
+
+		shorter, err := mapster.Map[[3]float64]([]int{1, 2, 3, 4})
+		if err != nil {
+			t.Fatalf("Map failed: %v", err)
+		}
+		if shorter != [3]float64{1, 2, 3} {
+			t.Errorf("Expected [1 2 3], got %v", shorter)
+		}
+
+		longer, err := mapster.Map[[3]uint]([]int8{5})
+		if err != nil {
+			t.Fatalf("Map failed: %v", err)
+		}
+		if longer != [3]uint{5, 0, 0} {
+			t.Errorf("Expected [5 0 0], got %v", longer)
+		}
+	})
+
+	t.Run("Identical elements", func(t *testing.T) {
+		src := []int{1, 2, 3}
+		ints, err := mapster.Map[PrimitiveInts](src)
+		if err != nil {
+			t.Fatalf("Map failed: %v", err)
+		}
+		src[0] = 100
+		if !reflect.DeepEqual(ints, PrimitiveInts{1, 2, 3}) {
+			t.Errorf("Expected an independent copy [1 2 3], got %v", ints)
+		}
+
+		points := []PrimitivePoint{{1, 2}, {3, 4}}
+		copied, err := mapster.Map[PrimitivePoints](points)
+		if err != nil {
+			t.Fatalf("Map failed: %v", err)
+		}
+		if !reflect.DeepEqual(copied, PrimitivePoints{{1, 2}, {3, 4}}) {
+			t.Errorf("Expected copied points, got %v", copied)
+		}
+	})
+
+	t.Run("Weakly typed", func(t *testing.T) {
+		_, err := mapster.Map[[]int]([]float64{1, 2.5}, mapster.WeaklyTyped())
+		if !errors.Is(err, mapster.ErrUnconvertible) {
+			t.Fatalf("Expected ErrUnconvertible for a non-integral element, got %v", err)
+		}
+		var mappingErr *mapster.MappingError
+		if !errors.As(err, &mappingErr) || mappingErr.DstPath != "[1]" {
+			t.Errorf("Expected error at [1], got %v", err)
+		}
+	})
+
+	t.Run("Registered converter", func(t *testing.T) {
+		dollars, err := mapster.Map[[]PrimitiveDollars]([]PrimitiveCents{150, 5})
+		if err != nil {
+			t.Fatalf("Map failed: %v", err)
+		}
+		if !reflect.DeepEqual(dollars, []PrimitiveDollars{1.5, 0.05}) {
+			t.Errorf("Expected converter applied to each element, got %v", dollars)
+		}
+	})
+
+	t.Run("Reused capacity", func(t *testing.T) {
+		buf := make([]float64, 0, 8)
+		out, err := mapster.MapSliceInto(buf, []int32{1, 2})
+		if err != nil {
+			t.Fatalf("MapSliceInto failed: %v", err)
+		}
+		if !reflect.DeepEqual(out, []float64{1, 2}) || &out[0] != &buf[:1][0] {
+			t.Errorf("Expected [1 2] in the reused buffer, got %v", out)
+		}
+	})
+}
@@ -0,0 +1,149 @@
+package tests
+
+import (
+	"errors"
+	"testing"
+
+	mapster "github.com/deferz/go-mapster"
+)
+
+// Types for remain field tests
+type Product struct {
+	ID    int
+	Name  string
+	Extra map[string]any `mapster:",remain"`
+}
+
+type ProductRecord struct {
+	ID     int
+	Name   string
+	Color  string
+	Weight float64
+}
+
+type RemainNode struct {
+	Name string
+	Next *RemainNode
+}
+
+type LinkedProduct struct {
+	ID     int
+	Parent *RemainNode
+	Extra  map[string]any `mapster:",remain"`
+}
+
+type LinkedProductRecord struct {
+	ID     int
+	Parent *RemainNode
+	Color  string
+}
+
+// TestRemainField tests collecting unmatched source members into a remain field
+type InvalidRemainProduct struct {
+	Name  string
+	Extra string `mapster:",remain"`
+}
+
+func TestRemainField(t *testing.T) {
+	t.Run("From map", func(t *testing.T) {
+		src := map[string]any{"ID": 1, "Name": "Desk", "Color": "oak", "Weight": 12.5}
+
+		dst, err := mapster.Map[Product](src)
+		if err != nil {
+			t.Fatalf("Map failed: %v", err)
+		}
+
+		if dst.ID != 1 || dst.Name != "Desk" {
+			t.Errorf("Expected {1 Desk}, got %+v", dst)
+		}
+		if len(dst.Extra) != 2 || dst.Extra["Color"] != "oak" || dst.Extra["Weight"] != 12.5 {
+			t.Errorf("Expected Color and Weight in Extra, got %v", dst.Extra)
+		}
+	})
+
+	t.Run("From struct", func(t *testing.T) {
+		src := ProductRecord{ID: 2, Name: "Chair", Color: "red", Weight: 4}
+
+		dst, err := mapster.Map[Product](src)
+		if err != nil {
+			t.Fatalf("Map failed: %v", err)
+		}
+
+		if len(dst.Extra) != 2 || dst.Extra["Color"] != "red" || dst.Extra["Weight"] != float64(4) {
+			t.Errorf("Expected Color and Weight in Extra, got %v", dst.Extra)
+		}
+	})
+
+	t.Run("Consumed members not encoded", func(t *testing.T) {
+		// A cyclic member fails to encode, but is consumed by a target field of the same type
+		parent := &RemainNode{Name: "root"}
+		parent.Next = parent
+		src := LinkedProductRecord{ID: 4, Parent: parent, Color: "blue"}
+
+		dst, err := mapster.Map[LinkedProduct](src)
+		if err != nil {
+			t.Fatalf("Map failed: %v", err)
+		}
+
+		if dst.Parent != parent {
+			t.Errorf("Expected Parent to be assigned, got %v", dst.Parent)
+		}
+		if len(dst.Extra) != 1 || dst.Extra["Color"] != "blue" {
+			t.Errorf("Expected only Color in Extra, got %v", dst.Extra)
+		}
+	})
+
+	t.Run("Nothing left", func(t *testing.T) {
+		dst, err := mapster.Map[Product](map[string]any{"ID": 3})
+		if err != nil {
+			t.Fatalf("Map failed: %v", err)
+		}
+		if dst.Extra != nil {
+			t.Errorf("Expected nil Extra, got %v", dst.Extra)
+		}
+	})
+
+	t.Run("Spread to map", func(t *testing.T) {
+		src := Product{ID: 4, Name: "Lamp", Extra: map[string]any{"Color": "white", "Name": "ignored"}}
+
+		dst, err := mapster.Map[map[string]any](src)
+		if err != nil {
+			t.Fatalf("Map failed: %v", err)
+		}
+
+		if dst["Color"] != "white" {
+			t.Errorf("Expected Color=white, got %v", dst["Color"])
+		}
+		if dst["Name"] != "Lamp" {
+			t.Errorf("Expected real field to win, got Name=%v", dst["Name"])
+		}
+		if _, exists := dst["Extra"]; exists {
+			t.Error("Expected remain field not to be stored as a key")
+		}
+	})
+
+	t.Run("Spread to struct", func(t *testing.T) {
+		src := Product{ID: 5, Name: "Shelf", Extra: map[string]any{"Color": "black", "Weight": 7.0}}
+
+		dst, err := mapster.Map[ProductRecord](src)
+		if err != nil {
+			t.Fatalf("Map failed: %v", err)
+		}
+
+		if dst.ID != 5 || dst.Color != "black" || dst.Weight != 7 {
+			t.Errorf("Expected {5 Shelf black 7}, got %+v", dst)
+		}
+	})
+
+	t.Run("Remain field not a map", func(t *testing.T) {
+		if _, err := mapster.Map[InvalidRemainProduct](map[string]any{"Name": "Lamp", "Color": "white"}); !errors.Is(err, mapster.ErrInvalidTag) {
+			t.Errorf("Expected ErrInvalidTag from a map, got %v", err)
+		}
+		if _, err := mapster.Map[InvalidRemainProduct](Product{Name: "Lamp"}); !errors.Is(err, mapster.ErrInvalidTag) {
+			t.Errorf("Expected ErrInvalidTag from a struct, got %v", err)
+		}
+		if _, err := mapster.Map[map[string]any](InvalidRemainProduct{Name: "Lamp"}); !errors.Is(err, mapster.ErrInvalidTag) {
+			t.Errorf("Expected ErrInvalidTag to a map, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,237 @@
+package tests
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	mapster "github.com/deferz/go-mapster"
+)
+
+// Types for collection reshaping tests
+type OrderID string
+
+type Order struct {
+	ID       OrderID
+	Customer Person
+	Total    float64
+}
+
+type OrderDTO struct {
+	ID    string
+	Total float64
+}
+
+type OrderEntry struct {
+	Key   OrderID
+	Value OrderDTO
+}
+
+type OrderKey struct {
+	Region string
+	Number int
+}
+
+type KeyValueSetting struct {
+	Key   string
+	Value string
+}
+
+// TestSliceToMap tests mapping slices to maps with key selectors
+func TestSliceToMap(t *testing.T) {
+	orders := []Order{
+		{ID: "a", Customer: Person{Name: "Ann"}, Total: 10},
+		{ID: "b", Customer: Person{Name: "Bob"}, Total: 20},
+	}
+
+	t.Run("Key field", func(t *testing.T) {
+		dst, err := mapster.Map[map[OrderID]OrderDTO](orders, mapster.KeyBy("ID"))
+		if err != nil {
+			t.Fatalf("Map failed: %v", err)
+		}
+
+		if len(dst) != 2 || dst["b"].Total != 20 || dst["a"].ID != "a" {
+			t.Errorf("Unexpected map: %+v", dst)
+		}
+	})
+
+	t.Run("Key path", func(t *testing.T) {
+		dst, err := mapster.Map[map[string]OrderDTO](orders, mapster.KeyBy("Customer.Name"))
+		if err != nil {
+			t.Fatalf("Map failed: %v", err)
+		}
+
+		if dst["Ann"].ID != "a" || dst["Bob"].ID != "b" {
+			t.Errorf("Unexpected map: %+v", dst)
+		}
+	})
+
+	t.Run("Key func", func(t *testing.T) {
+		upper := mapster.KeyByFunc(func(elem any) (any, error) {
+			return strings.ToUpper(string(elem.(Order).ID)), nil
+		})
+
+		dst, err := mapster.Map[map[string]OrderDTO](orders, upper)
+		if err != nil {
+			t.Fatalf("Map failed: %v", err)
+		}
+
+		if _, ok := dst["A"]; !ok {
+			t.Errorf("Expected key A, got %+v", dst)
+		}
+	})
+
+	t.Run("Duplicate keys", func(t *testing.T) {
+		duplicated := append(orders, Order{ID: "a", Total: 30})
+
+		if _, err := mapster.Map[map[OrderID]OrderDTO](duplicated, mapster.KeyBy("ID")); err == nil {
+			t.Fatal("Expected duplicate key error, got nil")
+		}
+
+		first, err := mapster.Map[map[OrderID]OrderDTO](duplicated,
+			mapster.KeyBy("ID"), mapster.OnDuplicateKey(mapster.DuplicateKeyKeepFirst))
+		if err != nil {
+			t.Fatalf("Map failed: %v", err)
+		}
+		if first["a"].Total != 10 {
+			t.Errorf("Expected first element kept, got %+v", first["a"])
+		}
+
+		last, err := mapster.Map[map[OrderID]OrderDTO](duplicated,
+			mapster.KeyBy("ID"), mapster.OnDuplicateKey(mapster.DuplicateKeyKeepLast))
+		if err != nil {
+			t.Fatalf("Map failed: %v", err)
+		}
+		if last["a"].Total != 30 {
+			t.Errorf("Expected last element kept, got %+v", last["a"])
+		}
+	})
+
+	t.Run("Missing selector", func(t *testing.T) {
+		if _, err := mapster.Map[map[OrderID]OrderDTO](orders); err == nil {
+			t.Fatal("Expected error without key selector, got nil")
+		}
+		if _, err := mapster.Map[map[OrderID]OrderDTO](orders, mapster.KeyBy("Missing")); err == nil {
+			t.Fatal("Expected error for unknown key field, got nil")
+		}
+	})
+}
+
+// TestMapToSlice tests mapping maps to slices with deterministic ordering
+func TestMapToSlice(t *testing.T) {
+	src := map[OrderID]Order{
+		"c": {ID: "c", Total: 3},
+		"a": {ID: "a", Total: 1},
+		"b": {ID: "b", Total: 2},
+	}
+
+	t.Run("Values in key order", func(t *testing.T) {
+		dst, err := mapster.Map[[]OrderDTO](src)
+		if err != nil {
+			t.Fatalf("Map failed: %v", err)
+		}
+
+		if len(dst) != 3 || dst[0].ID != "a" || dst[1].ID != "b" || dst[2].ID != "c" {
+			t.Errorf("Expected values sorted by key, got %+v", dst)
+		}
+	})
+
+	t.Run("Custom order", func(t *testing.T) {
+		desc := mapster.SortKeysBy(func(a, b any) bool { return a.(OrderID) > b.(OrderID) })
+
+		dst, err := mapster.Map[[]OrderDTO](src, desc)
+		if err != nil {
+			t.Fatalf("Map failed: %v", err)
+		}
+
+		if dst[0].ID != "c" || dst[2].ID != "a" {
+			t.Errorf("Expected values in descending key order, got %+v", dst)
+		}
+	})
+
+	t.Run("Key/value pairs", func(t *testing.T) {
+		dst, err := mapster.Map[[]OrderEntry](src)
+		if err != nil {
+			t.Fatalf("Map failed: %v", err)
+		}
+
+		if len(dst) != 3 || dst[1].Key != "b" || dst[1].Value.Total != 2 {
+			t.Errorf("Expected key/value pairs sorted by key, got %+v", dst)
+		}
+	})
+
+	t.Run("Values shaped as pairs", func(t *testing.T) {
+		settings := map[string]KeyValueSetting{
+			"b": {Key: "theme", Value: "dark"},
+			"a": {Key: "lang", Value: "en"},
+		}
+
+		dst, err := mapster.Map[[]KeyValueSetting](settings)
+		if err != nil {
+			t.Fatalf("Map failed: %v", err)
+		}
+
+		if len(dst) != 2 || dst[0] != settings["a"] || dst[1] != settings["b"] {
+			t.Errorf("Expected the map values sorted by key, got %+v", dst)
+		}
+	})
+
+	t.Run("Keys held in interfaces", func(t *testing.T) {
+		dst, err := mapster.Map[[]OrderDTO](map[any]Order{"b": src["b"], "a": src["a"]})
+		if err != nil {
+			t.Fatalf("Map failed: %v", err)
+		}
+
+		if len(dst) != 2 || dst[0].ID != "a" || dst[1].ID != "b" {
+			t.Errorf("Expected values sorted by key, got %+v", dst)
+		}
+
+		_, err = mapster.Map[[]OrderDTO](map[any]Order{"a": src["a"], 1: src["b"]})
+		if !errors.Is(err, mapster.ErrUnconvertible) {
+			t.Errorf("Expected ErrUnconvertible for keys of mixed kinds, got %v", err)
+		}
+	})
+
+	t.Run("Keys without natural order", func(t *testing.T) {
+		byKey := map[OrderKey]Order{
+			{Region: "eu", Number: 2}: {ID: "b"},
+			{Region: "eu", Number: 1}: {ID: "a"},
+			{Region: "us", Number: 1}: {ID: "c"},
+		}
+
+		_, err := mapster.Map[[]OrderDTO](byKey)
+		if !errors.Is(err, mapster.ErrUnconvertible) {
+			t.Errorf("Expected ErrUnconvertible without SortKeysBy, got %v", err)
+		}
+
+		byRegion := mapster.SortKeysBy(func(a, b any) bool {
+			x, y := a.(OrderKey), b.(OrderKey)
+			if x.Region != y.Region {
+				return x.Region < y.Region
+			}
+			return x.Number < y.Number
+		})
+		dst, err := mapster.Map[[]OrderDTO](byKey, byRegion)
+		if err != nil {
+			t.Fatalf("Map failed: %v", err)
+		}
+		if dst[0].ID != "a" || dst[1].ID != "b" || dst[2].ID != "c" {
+			t.Errorf("Expected values in custom key order, got %+v", dst)
+		}
+	})
+
+	t.Run("Round trip", func(t *testing.T) {
+		dst, err := mapster.Map[[]Order](src)
+		if err != nil {
+			t.Fatalf("Map failed: %v", err)
+		}
+		back, err := mapster.Map[map[OrderID]Order](dst, mapster.KeyBy("ID"))
+		if err != nil {
+			t.Fatalf("Map failed: %v", err)
+		}
+
+		if len(back) != len(src) || back["c"].Total != 3 {
+			t.Errorf("Round trip mismatch: %+v", back)
+		}
+	})
+}
@@ -0,0 +1,218 @@
+package tests
+
+import (
+	"testing"
+
+	mapster "github.com/deferz/go-mapster"
+)
+
+// Types for allocation reuse tests
+type ReuseItem struct {
+	ID   int
+	Name string
+}
+
+type ReuseItemDTO struct {
+	ID    int64
+	Name  string
+	Extra string
+}
+
+type ReuseOrder struct {
+	Items  []ReuseItem
+	Labels map[string]int
+}
+
+type ReuseOrderDTO struct {
+	Items  []ReuseItemDTO
+	Labels map[string]int64
+}
+
+// TestMapSliceInto tests mapping into a preallocated slice
+func TestMapSliceInto(t *testing.T) {
+	src := []ReuseItem{{ID: 1, Name: "a"}, {ID: 2, Name: "b"}}
+
+	t.Run("Reuses capacity", func(t *testing.T) {
+		buf := make([]ReuseItemDTO, 0, 8)
+		buf = append(buf, ReuseItemDTO{ID: 9, Extra: "stale"})
+
+		dst, err := mapster.MapSliceInto(buf, src)
+		if err != nil {
+			t.Fatalf("MapSliceInto failed: %v", err)
+		}
+		if len(dst) != 2 || &dst[0] != &buf[:1][0] {
+			t.Fatalf("Expected 2 elements in the same backing array, got %d", len(dst))
+		}
+		if dst[0].ID != 1 || dst[0].Extra != "" || dst[1].Name != "b" {
+			t.Errorf("Expected reset and mapped elements, got %+v", dst)
+		}
+	})
+
+	t.Run("Grows when too small", func(t *testing.T) {
+		dst, err := mapster.MapSliceInto(make([]ReuseItemDTO, 0, 1), src)
+		if err != nil {
+			t.Fatalf("MapSliceInto failed: %v", err)
+		}
+		if len(dst) != 2 || dst[1].ID != 2 {
+			t.Errorf("Expected 2 mapped elements, got %+v", dst)
+		}
+	})
+
+	t.Run("Shrinks", func(t *testing.T) {
+		dst, err := mapster.MapSliceInto(make([]ReuseItemDTO, 5), src[:1])
+		if err != nil {
+			t.Fatalf("MapSliceInto failed: %v", err)
+		}
+		if len(dst) != 1 || dst[0].ID != 1 {
+			t.Errorf("Expected 1 mapped element, got %+v", dst)
+		}
+	})
+
+	t.Run("Target sharing the source", func(t *testing.T) {
+		items := []ReuseItem{{ID: 1, Name: "a"}, {ID: 2, Name: "b"}, {ID: 3, Name: "c"}}
+
+		dst, err := mapster.MapSliceInto(items, items)
+		if err != nil {
+			t.Fatalf("MapSliceInto failed: %v", err)
+		}
+		if len(dst) != 3 || dst[0].ID != 1 || dst[2].Name != "c" {
+			t.Errorf("Expected the source elements, got %+v", dst)
+		}
+
+		shifted, err := mapster.MapSliceInto(items[1:1], items[:2])
+		if err != nil {
+			t.Fatalf("MapSliceInto failed: %v", err)
+		}
+		if shifted[0].ID != 1 || shifted[1].ID != 2 || items[1].ID != 2 {
+			t.Errorf("Expected the source left intact, got %+v and %+v", shifted, items)
+		}
+	})
+
+	t.Run("No allocations for primitives", func(t *testing.T) {
+		ints := []int32{1, 2, 3, 4}
+		buf := make([]int64, 0, 4)
+		allocs := testing.AllocsPerRun(100, func() {
+			buf, _ = mapster.MapSliceInto(buf[:0], ints)
+		})
+		if buf[3] != 4 {
+			t.Errorf("Expected 4, got %d", buf[3])
+		}
+		if _, err := mapster.MapSlice[int32, int64](ints); err != nil {
+			t.Fatal(err)
+		}
+		baseline := testing.AllocsPerRun(100, func() {
+			_, _ = mapster.MapSlice[int32, int64](ints)
+		})
+		if allocs >= baseline {
+			t.Errorf("Expected fewer allocations than MapSlice (%v), got %v", baseline, allocs)
+		}
+	})
+}
+
+// TestReuseCapacity tests reusing target slices and maps in MapTo
+func TestReuseCapacity(t *testing.T) {
+	src := ReuseOrder{Items: []ReuseItem{{ID: 1}, {ID: 2}}, Labels: map[string]int{"x": 1}}
+
+	t.Run("Replaces by default", func(t *testing.T) {
+		items := make([]ReuseItemDTO, 0, 4)
+		labels := map[string]int64{"old": 1}
+		dst := ReuseOrderDTO{Items: items, Labels: labels}
+
+		if err := mapster.MapTo(src, &dst); err != nil {
+			t.Fatalf("MapTo failed: %v", err)
+		}
+		if cap(dst.Items) == 4 {
+			t.Error("Expected a new slice without ReuseCapacity")
+		}
+		if len(labels) != 1 || labels["old"] != 1 {
+			t.Errorf("Expected old map untouched, got %v", labels)
+		}
+	})
+
+	t.Run("Reuses", func(t *testing.T) {
+		items := make([]ReuseItemDTO, 3, 4)
+		items[1].Extra = "stale"
+		labels := map[string]int64{"old": 1}
+		dst := ReuseOrderDTO{Items: items, Labels: labels}
+
+		if err := mapster.MapTo(src, &dst, mapster.ReuseCapacity()); err != nil {
+			t.Fatalf("MapTo failed: %v", err)
+		}
+		if len(dst.Items) != 2 || &dst.Items[0] != &items[0] {
+			t.Fatal("Expected the existing backing array to be reused")
+		}
+		if dst.Items[1].ID != 2 || dst.Items[1].Extra != "" {
+			t.Errorf("Expected reset and mapped element, got %+v", dst.Items[1])
+		}
+		if len(labels) != 1 || labels["x"] != 1 {
+			t.Errorf("Expected existing map to be reused, got %v", labels)
+		}
+	})
+
+	t.Run("Same storage", func(t *testing.T) {
+		values := []int{1, 2, 3}
+		holder := struct{ Values []int }{Values: values}
+
+		if err := mapster.MapTo(holder, &holder, mapster.ReuseCapacity(), mapster.FieldMask("Values")); err != nil {
+			t.Fatalf("MapTo failed: %v", err)
+		}
+		if holder.Values[2] != 3 {
+			t.Errorf("Expected values kept, got %v", holder.Values)
+		}
+	})
+}
+
+// TestPool tests reusing destination objects
+func TestPool(t *testing.T) {
+	t.Run("Map and put", func(t *testing.T) {
+		pool := mapster.NewPool[ReuseItemDTO](nil)
+
+		dst, err := pool.Map(ReuseItem{ID: 1, Name: "a"})
+		if err != nil {
+			t.Fatalf("Map failed: %v", err)
+		}
+		if dst.ID != 1 || dst.Name != "a" {
+			t.Errorf("Expected mapped item, got %+v", dst)
+		}
+
+		dst.Extra = "stale"
+		pool.Put(dst)
+
+		next, err := pool.Map(ReuseItem{ID: 2})
+		if err != nil {
+			t.Fatalf("Map failed: %v", err)
+		}
+		if next.ID != 2 || next.Extra != "" || next.Name != "" {
+			t.Errorf("Expected reset item, got %+v", next)
+		}
+	})
+
+	t.Run("Reset keeps capacity", func(t *testing.T) {
+		pool := mapster.NewPool[ReuseOrderDTO](func(o *ReuseOrderDTO) {
+			o.Items = o.Items[:0]
+		})
+		src := ReuseOrder{Items: []ReuseItem{{ID: 1}, {ID: 2}}}
+
+		dst, err := pool.Map(src, mapster.ReuseCapacity())
+		if err != nil {
+			t.Fatalf("Map failed: %v", err)
+		}
+		pool.Put(dst)
+
+		dst, err = pool.Map(src, mapster.ReuseCapacity())
+		if err != nil {
+			t.Fatalf("Map failed: %v", err)
+		}
+		if len(dst.Items) != 2 || dst.Items[1].ID != 2 {
+			t.Errorf("Expected mapped items, got %+v", dst.Items)
+		}
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		pool := mapster.NewPool[ReuseItemDTO](nil)
+		dst, err := pool.Map(nil)
+		if err == nil || dst != nil {
+			t.Errorf("Expected error and nil destination, got %v, %v", dst, err)
+		}
+	})
+}
@@ -0,0 +1,114 @@
+package tests
+
+import (
+	"errors"
+	"testing"
+
+	mapster "github.com/deferz/go-mapster"
+)
+
+// Types for sentinel error tests
+type SentinelItem struct {
+	ID   int
+	Name string
+}
+
+type SentinelItemDTO struct {
+	ID   int
+	Name string
+}
+
+type SentinelTagged struct {
+	Tags []string
+}
+
+// TestSentinelErrors tests that mapping failures wrap the exported sentinel errors
+func TestSentinelErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		target error
+		run    func() error
+	}{
+		{"Nil source", mapster.ErrNilSource, func() error {
+			_, err := mapster.Map[SentinelItemDTO](nil)
+			return err
+		}},
+		{"Nil source in MapTo", mapster.ErrNilSource, func() error {
+			var dst SentinelItemDTO
+			return mapster.MapTo(nil, &dst)
+		}},
+		{"Nil destination", mapster.ErrNilDestination, func() error {
+			return mapster.MapTo[SentinelItemDTO](SentinelItem{}, nil)
+		}},
+		{"Unconvertible", mapster.ErrUnconvertible, func() error {
+			_, err := mapster.Map[struct{ ID []int }](SentinelItem{ID: 1})
+			return err
+		}},
+		{"Unconvertible weakly typed", mapster.ErrUnconvertible, func() error {
+			_, err := mapster.Map[SentinelItemDTO](map[string]any{"ID": "x"}, mapster.WeaklyTyped())
+			return err
+		}},
+		{"Cycle", mapster.ErrCycle, func() error {
+			_, err := mapster.Map[*TreeNodeDTO](newTree())
+			return err
+		}},
+		{"Limit exceeded", mapster.ErrLimitExceeded, func() error {
+			_, err := mapster.Map[*LimitNodeDTO](newLimitChain(10), mapster.MaxDepth(3))
+			return err
+		}},
+		{"Unmapped mask member", mapster.ErrUnmappedMember, func() error {
+			_, err := mapster.Map[SentinelItemDTO](SentinelItem{}, mapster.FieldMask("Missing"))
+			return err
+		}},
+		{"Unmapped key member", mapster.ErrUnmappedMember, func() error {
+			_, err := mapster.Map[map[int]SentinelItemDTO]([]SentinelItem{{ID: 1}}, mapster.KeyBy("Missing"))
+			return err
+		}},
+		{"Missing key selector", mapster.ErrMissingKey, func() error {
+			_, err := mapster.Map[map[int]SentinelItemDTO]([]SentinelItem{{ID: 1}})
+			return err
+		}},
+		{"Nil selected key", mapster.ErrMissingKey, func() error {
+			nilKey := mapster.KeyByFunc(func(elem any) (any, error) { return nil, nil })
+			_, err := mapster.Map[map[int]SentinelItemDTO]([]SentinelItem{{ID: 1}}, nilKey)
+			return err
+		}},
+		{"Missing sync key", mapster.ErrMissingKey, func() error {
+			dst := []SentinelItemDTO{{ID: 1}}
+			return mapster.MapTo([]SentinelItem{{ID: 1}}, &dst, mapster.SyncCollectionsByKey("", false))
+		}},
+		{"Duplicate key", mapster.ErrDuplicateKey, func() error {
+			_, err := mapster.Map[map[int]SentinelItemDTO]([]SentinelItem{{ID: 1}, {ID: 1}}, mapster.KeyBy("ID"))
+			return err
+		}},
+		{"Incomparable sync key", mapster.ErrUnconvertible, func() error {
+			dst := []SentinelTagged{{Tags: []string{"a"}}}
+			return mapster.MapTo([]SentinelTagged{{Tags: []string{"a"}}}, &dst, mapster.SyncCollectionsByKey("Tags", false))
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.run()
+			if !errors.Is(err, tt.target) {
+				t.Errorf("Expected %v, got %v", tt.target, err)
+			}
+		})
+	}
+
+	t.Run("Limit error still available", func(t *testing.T) {
+		_, err := mapster.Map[*LimitNodeDTO](newLimitChain(10), mapster.MaxDepth(3))
+
+		var limitErr *mapster.LimitError
+		if !errors.As(err, &limitErr) {
+			t.Errorf("Expected LimitError, got %v", err)
+		}
+	})
+
+	t.Run("Messages unchanged", func(t *testing.T) {
+		_, err := mapster.Map[SentinelItemDTO](nil)
+		if err == nil || err.Error() != "source cannot be nil" {
+			t.Errorf("Expected source cannot be nil, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,66 @@
+//go:build go1.23
+
+package tests
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	mapster "github.com/deferz/go-mapster"
+)
+
+// TestMapSeq tests mapping values yielded by an iterator
+func TestMapSeq(t *testing.T) {
+	t.Run("In order", func(t *testing.T) {
+		rows := []StreamRow{{ID: 1, Price: 1.5}, {ID: 2, Price: 2}}
+
+		var got []StreamRowDTO
+		for row, err := range mapster.MapSeq[StreamRow, StreamRowDTO](slices.Values(rows)) {
+			if err != nil {
+				t.Fatalf("MapSeq failed: %v", err)
+			}
+			got = append(got, row)
+		}
+		if len(got) != 2 || got[0].Price != 1.5 || got[1].ID != 2 {
+			t.Errorf("Expected 2 mapped rows in order, got %+v", got)
+		}
+	})
+
+	t.Run("Error with index", func(t *testing.T) {
+		rows := []StreamRow{{ID: 1, Price: 1.0}, {ID: 2, Price: 2.0}, {ID: 3, Price: "bad"}, {ID: 4, Price: 4.0}}
+
+		var errs []error
+		count := 0
+		for _, err := range mapster.MapSeq[StreamRow, StreamRowDTO](slices.Values(rows)) {
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			count++
+		}
+		if count != 2 || len(errs) != 1 {
+			t.Fatalf("Expected 2 rows and 1 error, got %d rows and %v", count, errs)
+		}
+
+		var mappingErr *mapster.MappingError
+		if !errors.As(errs[0], &mappingErr) || mappingErr.DstPath != "[2].Price" {
+			t.Errorf("Expected failure at [2].Price, got %v", errs[0])
+		}
+	})
+
+	t.Run("Break", func(t *testing.T) {
+		rows := []StreamRow{{ID: 1}, {ID: 2}, {ID: 3}}
+
+		count := 0
+		for range mapster.MapSeq[StreamRow, StreamRowDTO](slices.Values(rows)) {
+			count++
+			if count == 2 {
+				break
+			}
+		}
+		if count != 2 {
+			t.Errorf("Expected 2 iterations, got %d", count)
+		}
+	})
+}
@@ -0,0 +1,133 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	mapster "github.com/deferz/go-mapster"
+)
+
+// Types for streaming tests
+type StreamRow struct {
+	ID    int
+	Price any
+}
+
+type StreamRowDTO struct {
+	ID    int64
+	Price float64
+}
+
+type StreamBatch struct {
+	ID    int
+	Lines []int
+}
+
+type StreamBatchDTO struct {
+	ID    int
+	Lines []int64
+}
+
+func sendRows(rows ...StreamRow) <-chan StreamRow {
+	ch := make(chan StreamRow, len(rows))
+	for _, row := range rows {
+		ch <- row
+	}
+	close(ch)
+	return ch
+}
+
+// TestMapStream tests mapping values received from a channel
+func TestMapStream(t *testing.T) {
+	t.Run("In order", func(t *testing.T) {
+		out, errs := mapster.MapStream[StreamRow, StreamRowDTO](context.Background(),
+			sendRows(StreamRow{ID: 1, Price: 1.5}, StreamRow{ID: 2, Price: 2}, StreamRow{ID: 3, Price: 3}))
+
+		var got []StreamRowDTO
+		for row := range out {
+			got = append(got, row)
+		}
+		if err := <-errs; err != nil {
+			t.Fatalf("MapStream failed: %v", err)
+		}
+		if len(got) != 3 || got[0].Price != 1.5 || got[2].ID != 3 {
+			t.Errorf("Expected 3 mapped rows in order, got %+v", got)
+		}
+	})
+
+	t.Run("Error with index", func(t *testing.T) {
+		out, errs := mapster.MapStream[StreamRow, StreamRowDTO](context.Background(),
+			sendRows(StreamRow{ID: 1, Price: 1.0}, StreamRow{ID: 2, Price: "bad"}, StreamRow{ID: 3, Price: 3.0}))
+
+		count := 0
+		for range out {
+			count++
+		}
+		if count != 1 {
+			t.Errorf("Expected 1 row before the failure, got %d", count)
+		}
+
+		var mappingErr *mapster.MappingError
+		if err := <-errs; !errors.As(err, &mappingErr) {
+			t.Fatalf("Expected MappingError, got %v", err)
+		}
+		if mappingErr.DstPath != "[1].Price" {
+			t.Errorf("Expected path [1].Price, got %s", mappingErr.DstPath)
+		}
+	})
+
+	t.Run("Cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		src := make(chan StreamRow)
+
+		out, errs := mapster.MapStream[StreamRow, StreamRowDTO](ctx, src)
+		src <- StreamRow{ID: 1, Price: 1.0}
+		if row := <-out; row.ID != 1 {
+			t.Errorf("Expected ID 1, got %d", row.ID)
+		}
+		cancel()
+
+		if err := <-errs; !errors.Is(err, context.Canceled) {
+			t.Errorf("Expected context.Canceled, got %v", err)
+		}
+		if _, ok := <-out; ok {
+			t.Error("Expected output channel to be closed")
+		}
+	})
+
+	t.Run("Limits per element", func(t *testing.T) {
+		// Each batch stays under the limit while the stream as a whole exceeds it
+		src := make(chan StreamBatch, 3)
+		for i := 1; i <= 3; i++ {
+			src <- StreamBatch{ID: i, Lines: []int{i, i * 10}}
+		}
+		close(src)
+
+		out, errs := mapster.MapStream[StreamBatch, StreamBatchDTO](context.Background(), src, mapster.MaxElements(3))
+
+		var got []StreamBatchDTO
+		for batch := range out {
+			got = append(got, batch)
+		}
+		if err := <-errs; err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if len(got) != 3 || got[2].Lines[1] != 30 {
+			t.Errorf("Expected 3 mapped batches, got %+v", got)
+		}
+	})
+
+	t.Run("Options", func(t *testing.T) {
+		out, errs := mapster.MapStream[StreamRow, StreamRowDTO](context.Background(),
+			sendRows(StreamRow{ID: 1, Price: 2.0}), mapster.FieldMask("Price"))
+
+		row := <-out
+		if row.ID != 0 || row.Price != 2 {
+			t.Errorf("Expected only Price to be mapped, got %+v", row)
+		}
+		if err := <-errs; err != nil {
+			t.Errorf("Expected no error, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,156 @@
+package tests
+
+import (
+	"testing"
+
+	mapster "github.com/deferz/go-mapster"
+)
+
+// Types for struct <-> map tests
+type MapAudit struct {
+	CreatedBy string
+}
+
+type MapOrder struct {
+	MapAudit
+	ID      int
+	Address Address
+	Tags    []string
+	Items   []Item
+	Owner   *Person
+}
+
+// TestStructToMap tests mapping from structs to maps with string keys
+func TestStructToMap(t *testing.T) {
+	t.Run("To map[string]any", func(t *testing.T) {
+		src := MapOrder{
+			MapAudit: MapAudit{CreatedBy: "admin"},
+			ID:       7,
+			Address:  Address{City: "Paris"},
+			Tags:     []string{"a", "b"},
+			Items:    []Item{{ID: 1, Name: "Item 1"}},
+		}
+
+		dst, err := mapster.Map[map[string]any](src)
+		if err != nil {
+			t.Fatalf("Map failed: %v", err)
+		}
+
+		if dst["ID"] != 7 {
+			t.Errorf("Expected ID=7, got %v", dst["ID"])
+		}
+		if dst["CreatedBy"] != "admin" {
+			t.Errorf("Expected promoted CreatedBy=admin, got %v", dst["CreatedBy"])
+		}
+		address, ok := dst["Address"].(map[string]any)
+		if !ok || address["City"] != "Paris" {
+			t.Errorf("Expected nested address map, got %#v", dst["Address"])
+		}
+		if tags, ok := dst["Tags"].([]string); !ok || len(tags) != 2 {
+			t.Errorf("Expected Tags to stay []string, got %#v", dst["Tags"])
+		}
+		items, ok := dst["Items"].([]any)
+		if !ok || len(items) != 1 {
+			t.Fatalf("Expected Items as []any, got %#v", dst["Items"])
+		}
+		if item, ok := items[0].(map[string]any); !ok || item["Name"] != "Item 1" {
+			t.Errorf("Expected item map, got %#v", items[0])
+		}
+		if owner, exists := dst["Owner"]; !exists || owner != nil {
+			t.Errorf("Expected nil Owner entry, got %#v", owner)
+		}
+	})
+
+	t.Run("To map[string]string", func(t *testing.T) {
+		type Flags struct {
+			Name    string
+			Count   int
+			Enabled bool
+			Ratio   *float64
+		}
+		ratio := 0.5
+
+		dst, err := mapster.Map[map[string]string](Flags{Name: "x", Count: 3, Enabled: true, Ratio: &ratio})
+		if err != nil {
+			t.Fatalf("Map failed: %v", err)
+		}
+
+		expected := map[string]string{"Name": "x", "Count": "3", "Enabled": "true", "Ratio": "0.5"}
+		for key, value := range expected {
+			if dst[key] != value {
+				t.Errorf("Expected %s=%s, got %s", key, value, dst[key])
+			}
+		}
+	})
+}
+
+// TestMapToStruct tests mapping from maps with string keys to structs
+func TestMapToStruct(t *testing.T) {
+	t.Run("From map[string]any", func(t *testing.T) {
+		src := map[string]any{
+			"ID":        7,
+			"CreatedBy": "admin",
+			"Address":   map[string]any{"City": "Paris", "Country": "FR"},
+			"Tags":      []any{"a", "b"},
+			"Items":     []any{map[string]any{"ID": 1, "Name": "Item 1"}},
+			"Owner":     map[string]any{"Name": "Bob"},
+			"Unknown":   true,
+		}
+
+		dst, err := mapster.Map[MapOrder](src)
+		if err != nil {
+			t.Fatalf("Map failed: %v", err)
+		}
+
+		if dst.ID != 7 || dst.CreatedBy != "admin" {
+			t.Errorf("Expected ID=7 and CreatedBy=admin, got %+v", dst)
+		}
+		if dst.Address.City != "Paris" || dst.Address.Country != "FR" {
+			t.Errorf("Expected address in Paris, FR, got %+v", dst.Address)
+		}
+		if len(dst.Tags) != 2 || dst.Tags[1] != "b" {
+			t.Errorf("Expected tags [a b], got %v", dst.Tags)
+		}
+		if len(dst.Items) != 1 || dst.Items[0].Name != "Item 1" {
+			t.Errorf("Expected one item, got %+v", dst.Items)
+		}
+		if dst.Owner == nil || dst.Owner.Name != "Bob" {
+			t.Errorf("Expected owner Bob, got %+v", dst.Owner)
+		}
+	})
+
+	t.Run("MapTo keeps missing fields", func(t *testing.T) {
+		dst := Target{Name: "old", Email: "old@example.com"}
+
+		if err := mapster.MapTo(map[string]string{"Name": "new"}, &dst); err != nil {
+			t.Fatalf("MapTo failed: %v", err)
+		}
+
+		if dst.Name != "new" || dst.Email != "old@example.com" {
+			t.Errorf("Expected Name=new and Email kept, got %+v", dst)
+		}
+	})
+
+	t.Run("Round trip", func(t *testing.T) {
+		src := MapOrder{ID: 1, Address: Address{Street: "Main"}, Items: []Item{{ID: 2}}}
+
+		generic, err := mapster.Map[map[string]any](src)
+		if err != nil {
+			t.Fatalf("Map to map failed: %v", err)
+		}
+		dst, err := mapster.Map[MapOrder](generic)
+		if err != nil {
+			t.Fatalf("Map to struct failed: %v", err)
+		}
+
+		if dst.ID != 1 || dst.Address.Street != "Main" || len(dst.Items) != 1 || dst.Items[0].ID != 2 {
+			t.Errorf("Round trip mismatch: %+v", dst)
+		}
+	})
+
+	t.Run("Unconvertible value", func(t *testing.T) {
+		if _, err := mapster.Map[Target](map[string]any{"Age": "thirty"}); err == nil {
+			t.Fatal("Expected error for string to int, got nil")
+		}
+	})
+}
@@ -0,0 +1,234 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	mapster "github.com/deferz/go-mapster"
+)
+
+// Types for typed helper tests
+type TypedUser struct {
+	ID    int
+	Name  string
+	Score any
+}
+
+type TypedUserDTO struct {
+	ID    int64
+	Name  string
+	Score float64
+}
+
+type PlanShipment struct {
+	City string
+}
+
+type PlanOrder struct {
+	BaseEmbedded
+	Shipment PlanShipment
+	Total    int
+}
+
+type PlanOrderDTO struct {
+	ID    int64
+	Name  string
+	City  string
+	Total float64
+	Label string
+}
+
+// TestMapSlice tests mapping slices with both element types known
+func TestMapSlice(t *testing.T) {
+	t.Run("Elements", func(t *testing.T) {
+		src := []TypedUser{{ID: 1, Name: "Ann", Score: 1.5}, {ID: 2, Name: "Bob", Score: 2}}
+
+		dst, err := mapster.MapSlice[TypedUser, TypedUserDTO](src)
+		if err != nil {
+			t.Fatalf("MapSlice failed: %v", err)
+		}
+		if len(dst) != 2 || dst[0].ID != 1 || dst[1].Name != "Bob" || dst[1].Score != 2 {
+			t.Errorf("Expected mapped users, got %+v", dst)
+		}
+	})
+
+	t.Run("Nil", func(t *testing.T) {
+		dst, err := mapster.MapSlice[TypedUser, TypedUserDTO](nil)
+		if err != nil || dst != nil {
+			t.Errorf("Expected nil slice, got %v, %v", dst, err)
+		}
+	})
+
+	t.Run("Empty", func(t *testing.T) {
+		dst, err := mapster.MapSlice[TypedUser, TypedUserDTO]([]TypedUser{})
+		if err != nil || dst == nil || len(dst) != 0 {
+			t.Errorf("Expected empty slice, got %v, %v", dst, err)
+		}
+	})
+
+	t.Run("Primitives", func(t *testing.T) {
+		dst, err := mapster.MapSlice[int32, int64]([]int32{1, 2, 3})
+		if err != nil {
+			t.Fatalf("MapSlice failed: %v", err)
+		}
+		if len(dst) != 3 || dst[2] != 3 {
+			t.Errorf("Expected [1 2 3], got %v", dst)
+		}
+	})
+
+	t.Run("Error path", func(t *testing.T) {
+		src := []TypedUser{{ID: 1, Score: 1.0}, {ID: 2, Score: "bad"}}
+
+		_, err := mapster.MapSlice[TypedUser, TypedUserDTO](src)
+
+		var mappingErr *mapster.MappingError
+		if !errors.As(err, &mappingErr) {
+			t.Fatalf("Expected MappingError, got %v", err)
+		}
+		if mappingErr.DstPath != "[1].Score" {
+			t.Errorf("Expected path [1].Score, got %s", mappingErr.DstPath)
+		}
+	})
+
+	t.Run("Options", func(t *testing.T) {
+		src := []TypedUser{{ID: 1, Name: "Ann", Score: "bad"}, {ID: 2, Name: "Bob", Score: 3.0}}
+
+		dst, err := mapster.MapSlice[TypedUser, TypedUserDTO](src, mapster.CollectErrors(), mapster.FieldMask("Name", "Score"))
+
+		var errs mapster.MappingErrors
+		if !errors.As(err, &errs) || len(errs) != 1 {
+			t.Fatalf("Expected 1 collected error, got %v", err)
+		}
+		if dst[1].Name != "Bob" || dst[1].Score != 3 || dst[1].ID != 0 {
+			t.Errorf("Expected masked mapping, got %+v", dst[1])
+		}
+	})
+
+	t.Run("Limits", func(t *testing.T) {
+		_, err := mapster.MapSlice[int, int]([]int{1, 2, 3}, mapster.MaxCollectionLen(2))
+		if !errors.Is(err, mapster.ErrLimitExceeded) {
+			t.Errorf("Expected ErrLimitExceeded, got %v", err)
+		}
+	})
+
+	t.Run("Same result as mapping each element", func(t *testing.T) {
+		src := []PlanOrder{
+			{BaseEmbedded: BaseEmbedded{ID: 1, Name: "Ann"}, Shipment: PlanShipment{City: "Oslo"}, Total: 3},
+			{BaseEmbedded: BaseEmbedded{ID: 2, Name: "Bob"}, Shipment: PlanShipment{City: "Rome"}, Total: 5},
+		}
+
+		dst, err := mapster.MapSlice[PlanOrder, PlanOrderDTO](src)
+		if err != nil {
+			t.Fatalf("MapSlice failed: %v", err)
+		}
+		for i := range src {
+			expected, err := mapster.Map[PlanOrderDTO](src[i])
+			if err != nil {
+				t.Fatalf("Map failed: %v", err)
+			}
+			if dst[i] != expected {
+				t.Errorf("Expected %+v, got %+v", expected, dst[i])
+			}
+		}
+		if dst[1].ID != 2 || dst[1].City != "Rome" || dst[1].Total != 5 {
+			t.Errorf("Expected embedded and nested members, got %+v", dst[1])
+		}
+	})
+
+	t.Run("Pair registered after first use", func(t *testing.T) {
+		src := []PlanOrder{{BaseEmbedded: BaseEmbedded{ID: 7, Name: "Ann"}}}
+		if _, err := mapster.MapSlice[PlanOrder, PlanOrderDTO](src); err != nil {
+			t.Fatalf("MapSlice failed: %v", err)
+		}
+
+		mapster.NewMapperConfig[PlanOrder, PlanOrderDTO]().
+			ForMember("Label", func(ctx context.Context, src PlanOrder) (any, error) {
+				return "order " + src.Name, nil
+			}).
+			AfterMap(func(ctx context.Context, src PlanOrder, dst *PlanOrderDTO) error {
+				dst.ID *= 10
+				return nil
+			}).
+			Register()
+
+		dst, err := mapster.MapSlice[PlanOrder, PlanOrderDTO](src)
+		if err != nil {
+			t.Fatalf("MapSlice failed: %v", err)
+		}
+		if dst[0].Label != "order Ann" || dst[0].ID != 70 {
+			t.Errorf("Expected the registered pair to apply, got %+v", dst[0])
+		}
+
+		values, err := mapster.MapMapValues[string, PlanOrder, PlanOrderDTO](map[string]PlanOrder{"a": src[0]})
+		if err != nil {
+			t.Fatalf("MapMapValues failed: %v", err)
+		}
+		if values["a"].Label != "order Ann" || values["a"].ID != 70 {
+			t.Errorf("Expected the registered pair to apply, got %+v", values["a"])
+		}
+	})
+}
+
+// TestMapMapValues tests mapping map values with all types known
+func TestMapMapValues(t *testing.T) {
+	t.Run("Values", func(t *testing.T) {
+		src := map[string]TypedUser{"a": {ID: 1, Name: "Ann"}, "b": {ID: 2, Name: "Bob"}}
+
+		dst, err := mapster.MapMapValues[string, TypedUser, TypedUserDTO](src)
+		if err != nil {
+			t.Fatalf("MapMapValues failed: %v", err)
+		}
+		if len(dst) != 2 || dst["a"].Name != "Ann" || dst["b"].ID != 2 {
+			t.Errorf("Expected mapped users, got %+v", dst)
+		}
+	})
+
+	t.Run("Nil", func(t *testing.T) {
+		dst, err := mapster.MapMapValues[string, TypedUser, TypedUserDTO](nil)
+		if err != nil || dst != nil {
+			t.Errorf("Expected nil map, got %v, %v", dst, err)
+		}
+	})
+
+	t.Run("Error path", func(t *testing.T) {
+		src := map[int]TypedUser{7: {Score: "bad"}}
+
+		_, err := mapster.MapMapValues[int, TypedUser, TypedUserDTO](src)
+
+		var mappingErr *mapster.MappingError
+		if !errors.As(err, &mappingErr) {
+			t.Fatalf("Expected MappingError, got %v", err)
+		}
+		if mappingErr.DstPath != "[7].Score" {
+			t.Errorf("Expected path [7].Score, got %s", mappingErr.DstPath)
+		}
+	})
+}
+
+// TestMapPtr tests mapping pointers with both types known
+func TestMapPtr(t *testing.T) {
+	t.Run("Value", func(t *testing.T) {
+		dst, err := mapster.MapPtr[TypedUser, TypedUserDTO](&TypedUser{ID: 1, Name: "Ann", Score: 4})
+		if err != nil {
+			t.Fatalf("MapPtr failed: %v", err)
+		}
+		if dst == nil || dst.ID != 1 || dst.Name != "Ann" || dst.Score != 4 {
+			t.Errorf("Expected mapped user, got %+v", dst)
+		}
+	})
+
+	t.Run("Nil", func(t *testing.T) {
+		dst, err := mapster.MapPtr[TypedUser, TypedUserDTO](nil)
+		if err != nil || dst != nil {
+			t.Errorf("Expected nil pointer, got %v, %v", dst, err)
+		}
+	})
+
+	t.Run("Cycles", func(t *testing.T) {
+		_, err := mapster.MapPtr[TreeNode, TreeNodeDTO](newTree())
+		if !errors.Is(err, mapster.ErrCycle) {
+			t.Errorf("Expected ErrCycle, got %v", err)
+		}
+	})
+}
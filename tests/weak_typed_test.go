@@ -0,0 +1,113 @@
+package tests
+
+import (
+	"testing"
+
+	mapster "github.com/deferz/go-mapster"
+)
+
+// Types for weakly typed mapping tests
+type ServerConfig struct {
+	Port    int
+	Debug   bool
+	Ratio   float64
+	Hosts   []string
+	Ports   []int
+	Timeout *int
+	Label   string
+}
+
+type PairConfigSource map[string]string
+
+type PairConfigTarget struct {
+	Port  int
+	Debug bool
+}
+
+func init() {
+	mapster.NewMapperConfig[PairConfigSource, PairConfigTarget]().
+		With(mapster.WeaklyTyped()).
+		Register()
+}
+
+// TestWeaklyTyped tests lenient conversions in weakly typed mode
+func TestWeaklyTyped(t *testing.T) {
+	t.Run("JSON-like map", func(t *testing.T) {
+		src := map[string]any{
+			"Port":    float64(8080),
+			"Debug":   "true",
+			"Ratio":   "0.25",
+			"Hosts":   "a.example.com, b.example.com",
+			"Ports":   float64(80),
+			"Timeout": "",
+			"Label":   float64(42),
+		}
+
+		dst, err := mapster.Map[ServerConfig](src, mapster.WeaklyTyped())
+		if err != nil {
+			t.Fatalf("Map failed: %v", err)
+		}
+
+		if dst.Port != 8080 {
+			t.Errorf("Expected Port=8080, got %d", dst.Port)
+		}
+		if !dst.Debug {
+			t.Error("Expected Debug=true")
+		}
+		if dst.Ratio != 0.25 {
+			t.Errorf("Expected Ratio=0.25, got %v", dst.Ratio)
+		}
+		if len(dst.Hosts) != 2 || dst.Hosts[0] != "a.example.com" || dst.Hosts[1] != "b.example.com" {
+			t.Errorf("Expected two hosts, got %q", dst.Hosts)
+		}
+		if len(dst.Ports) != 1 || dst.Ports[0] != 80 {
+			t.Errorf("Expected Ports=[80], got %v", dst.Ports)
+		}
+		if dst.Timeout != nil {
+			t.Errorf("Expected nil Timeout, got %v", *dst.Timeout)
+		}
+		if dst.Label != "42" {
+			t.Errorf("Expected Label=42, got %q", dst.Label)
+		}
+	})
+
+	t.Run("Boolean strings", func(t *testing.T) {
+		for input, expected := range map[string]bool{"1": true, "0": false, "true": true, "FALSE": false} {
+			dst, err := mapster.Map[bool](input, mapster.WeaklyTyped())
+			if err != nil {
+				t.Fatalf("Map %q failed: %v", input, err)
+			}
+			if dst != expected {
+				t.Errorf("Expected %q -> %v, got %v", input, expected, dst)
+			}
+		}
+	})
+
+	t.Run("Non-integral float", func(t *testing.T) {
+		if _, err := mapster.Map[ServerConfig](map[string]any{"Port": 80.5}, mapster.WeaklyTyped()); err == nil {
+			t.Fatal("Expected error for non-integral float, got nil")
+		}
+	})
+
+	t.Run("Overflow", func(t *testing.T) {
+		if _, err := mapster.Map[int8]("300", mapster.WeaklyTyped()); err == nil {
+			t.Fatal("Expected overflow error, got nil")
+		}
+	})
+
+	t.Run("Strict by default", func(t *testing.T) {
+		if _, err := mapster.Map[ServerConfig](map[string]any{"Debug": "true"}); err == nil {
+			t.Fatal("Expected error without weak typing, got nil")
+		}
+	})
+
+	t.Run("Pair scoped", func(t *testing.T) {
+		dst, err := mapster.Map[PairConfigTarget](PairConfigSource{"Port": "9000", "Debug": "1"})
+		if err != nil {
+			t.Fatalf("Map failed: %v", err)
+		}
+		if dst.Port != 9000 || !dst.Debug {
+			t.Errorf("Expected {9000 true}, got %+v", dst)
+		}
+	})
+}